@@ -1,9 +1,12 @@
 package app
 
 import (
+	"context"
 	"lockstep-core/src/internal/di"
 	"lockstep-core/src/internal/server"
 	"lockstep-core/src/pkg/lockstep/room"
+	"log"
+	"sync"
 )
 
 // NewHandlers 使用外部提供的 newGameWorld 构造函数初始化并返回 handlers
@@ -12,12 +15,58 @@ func NewHandlers(newGameWorld room.NewGameWorldFunc) (*server.Serverandlers, err
 	return di.InitializeWithGameWorld(newGameWorld)
 }
 
+// Server 可优雅关闭的服务器
+type Server struct {
+	handlers *server.Serverandlers
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// NewServer 初始化服务器并注册所有处理器
+func NewServer(newGameWorld room.NewGameWorldFunc) (*Server, error) {
+	handlers, err := NewHandlers(newGameWorld)
+	if err != nil {
+		return nil, err
+	}
+	handlers.RegisterHandlers()
+	return &Server{handlers: handlers}, nil
+}
+
+// Run 启动服务器并阻塞，直到服务器出错或 ctx 结束
+// ctx 结束后会调用 Shutdown 排空房间并关闭监听器
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.handlers.Start()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("🛑 Received stop signal, shutting down (timeout %v)", s.handlers.ShutdownTimeout())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.handlers.ShutdownTimeout())
+	defer cancel()
+	return s.Shutdown(shutdownCtx)
+}
+
+// Shutdown 停止接受新房间与新玩家，排空所有房间后关闭监听器
+// 可重复调用，只会执行一次
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.shutdownErr = s.handlers.Shutdown(ctx)
+	})
+	return s.shutdownErr
+}
+
 // StartWith 直接初始化并启动服务器（阻塞直到服务器返回或出错）
 func StartWith(newGameWorld room.NewGameWorldFunc) error {
-	handlers, err := NewHandlers(newGameWorld)
+	s, err := NewServer(newGameWorld)
 	if err != nil {
 		return err
 	}
-	handlers.RegisterHandlers()
-	return handlers.Start()
+	return s.Run(context.Background())
 }
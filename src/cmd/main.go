@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"lockstep-core/src/app"
@@ -9,7 +10,10 @@ import (
 	"lockstep-core/src/utils"
 	"lockstep-core/src/utils/tls"
 	"log"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 )
 
 func Verbose() {
@@ -33,8 +37,17 @@ func main() {
 		return
 	}
 
+	// 收到 SIGINT/SIGTERM 时优雅关闭，排空进行中的对局
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// 使用对外导出的 app 包启动，内部默认使用 internal/defaults.DefaultNewGameWorld
-	if err := app.StartWith(defaults.DefaultNewGameWorld); err != nil {
+	server, err := app.NewServer(defaults.DefaultNewGameWorld)
+	if err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+	if err := server.Run(ctx); err != nil {
+		log.Fatalf("Server stopped with error: %v", err)
+	}
+	log.Printf("Server stopped")
 }
@@ -32,9 +32,11 @@ func NewDefaultConfig(dataDir *string) (*RuntimeConfig, error) {
 		if _, err := toml.DecodeFile(generalCfgPath, &generalCfg); err != nil {
 			// 读取失败，使用默认值
 			log.Printf("读取配置文件 %s 失败，使用默认配置: %v", generalCfgPath, err)
-			generalCfg.ApplyDefaults()
+			generalCfg = GeneralConfig{}
 		}
 	}
+	// 配置文件中未出现的配置项使用默认值
+	generalCfg.ApplyDefaults()
 
 	// 从地址中提取主机部分用于证书生成
 	host := *generalCfg.Host
@@ -11,6 +11,10 @@ type ServerConfig struct {
 	HttpPort      *uint16 `toml:"http_port"`
 	GrpcPort      *uint16 `toml:"grpc_port"`
 	MaxRoomNumber *uint32 `toml:"max_room_number"`
+
+	// 关闭服务器时等待 InGame 房间进入 PostGame 的最长时间(秒)
+	// 超时后房间被强制关闭，0 为不等待
+	ShutdownDrainTimeout *uint32 `toml:"shutdown_drain_timeout"`
 }
 
 // http addr
@@ -21,6 +25,7 @@ func (c *ServerConfig) Addr() string {
 const DefaultHost = "127.0.0.1"
 const DefaultHttpPort = 4433
 const DefaultGrpcPort = 50051
+const DefaultShutdownDrainTimeout = 60 // 默认最多等待对局结束 1min
 
 type LockstepConfig struct {
 	// 帧间隔
@@ -34,22 +39,166 @@ type LockstepConfig struct {
 	// 是否启用确定性锁步，
 	// 如果此值为-1，表示乐观锁步
 	// 如果此值大于0，表示悲观锁步，且为等待确认的最大帧数
+	// 即客户端最多可提前 NextFrameID 多少帧提交输入
 	DeterministicLockstep *int32 `toml:"deterministic_lockstep"`
 
+	// 悲观锁步下等待迟到输入的超时时间(毫秒)
+	// 超时后以空输入代替迟到玩家的输入并步进
+	DeterministicTimeout *uint32 `toml:"deterministic_timeout"`
+
 	// 最大人数
 	MaxClientsPerRoom *uint16 `toml:"max_clients_per_room"`
+
+	// 每个房间最多容纳的观战者数量，观战者不占用玩家席位
+	// 0 为不允许观战
+	MaxSpectatorsPerRoom *uint16 `toml:"max_spectators_per_room"`
+
+	// 观战者收到的帧相对于最新帧延迟的帧数，防止观战者向玩家泄露信息
+	SpectatorDelayFrames *uint32 `toml:"spectator_delay_frames"`
+
+	// 断线玩家保留席位等待重连的时间(毫秒)
+	// 0 为断线立即移除
+	ReconnectGracePeriod *uint32 `toml:"reconnect_grace_period"`
+
+	// 每个房间最多保留的历史帧数量
+	// 早于所有客户端 ack 与最新快照的帧会被提前淘汰
+	FrameHistorySize *uint32 `toml:"frame_history_size"`
+
+	// 每个房间最多保留的快照数量
+	SnapshotHistorySize *uint32 `toml:"snapshot_history_size"`
+
+	// 每隔多少帧向游戏世界请求一次状态快照，用于重连与落后客户端追帧
+	// 0 为不生成快照
+	SnapshotInterval *uint32 `toml:"snapshot_interval"`
+
+	// 单个数据报最多携带的帧数量，超出时拆分为多个数据报
+	MaxFramesPerDatagram *uint16 `toml:"max_frames_per_datagram"`
+
+	// 单个数据报帧数据的最大字节数，超出时拆分为多个数据报
+	// 应小于会话的分片阈值 (session.MaxDatagramSize)，以免帧数据报再被分片
+	MaxDatagramBytes *uint32 `toml:"max_datagram_bytes"`
+
+	// 每一帧最多在连续多少次发送中重复携带
+	// 实际冗余度根据各客户端估计的丢包率在 1 到此值之间调整
+	MaxFrameRedundancy *uint16 `toml:"max_frame_redundancy"`
+
+	// 每个客户端出站队列最多排队的帧数据报数量，超出时丢弃最旧的数据报
+	// 控制消息不会被丢弃，积压超过此数量时同样视为饱和
+	SendQueueSize *uint16 `toml:"send_queue_size"`
+
+	// 出站队列持续饱和多久后断开该客户端 (ms)，断开后按正常断线处理
+	// 0 为不断开
+	SendQueueSaturationTimeout *uint32 `toml:"send_queue_saturation_timeout"`
+
+	// 准备阶段中，准备的玩家达到此百分比后开始准备倒计时
+	ReadyQuorumPercent *uint16 `toml:"ready_quorum_percent"`
+
+	// 准备倒计时的时长 (ms)，结束时仍未准备的玩家由游戏世界决定自动准备或被踢出
+	// 0 为不倒计时，一直等待所有玩家准备
+	ReadyCountdown *uint32 `toml:"ready_countdown"`
+
+	// 加载阶段的超时时间 (ms)，超时后由游戏世界决定踢出未加载完毕的玩家或直接开始游戏
+	// 0 为一直等待所有玩家加载完毕
+	LoadingTimeout *uint32 `toml:"loading_timeout"`
+
+	// 暂停与恢复对局所需的在线玩家投票比例 (%)
+	// 0 为只有房主可以暂停与恢复
+	PauseVotePercent *uint16 `toml:"pause_vote_percent"`
+
+	// 每名玩家每场对局可用的暂停时长 (ms)，用尽后自动恢复且不能再发起暂停
+	// 0 为不允许玩家暂停
+	MaxPauseBudget *uint32 `toml:"max_pause_budget"`
+
+	// 玩家在对局中断线时是否自动暂停，断线的玩家均已重连或离开后自动恢复
+	AutoPauseOnDisconnect *bool `toml:"auto_pause_on_disconnect"`
+
+	// 单个请求的最大字节数，超出的请求被丢弃并记为违规
+	MaxRequestBytes *uint32 `toml:"max_request_bytes"`
+
+	// 每个客户端每秒最多发送的请求数量，超出的请求被丢弃并记为违规
+	// 0 为不限制
+	MaxRequestsPerSecond *uint16 `toml:"max_requests_per_second"`
+
+	// 每一帧输入数据的最大字节数
+	MaxInputBytesPerFrame *uint32 `toml:"max_input_bytes_per_frame"`
+
+	// 输入的帧号最多领先服务端下一帧多少帧，更远的输入被丢弃并记为违规
+	MaxInputFramesAhead *uint32 `toml:"max_input_frames_ahead"`
+
+	// 客户端在 10s 内违规超过此次数时被踢出房间
+	// 0 为只丢弃违规请求而不踢出
+	MaxViolations *uint16 `toml:"max_violations"`
+
+	// 每个客户端最多同时订阅的 chunk 数量，包括默认的 chunk 0
+	MaxChunksPerClient *uint16 `toml:"max_chunks_per_client"`
+
+	// 对局回放文件的保存目录，每场对局生成一个回放文件
+	// 为空则不录制，DumpFrameHistory 只能导出内存中尚未淘汰的帧历史
+	ReplayDir *string `toml:"replay_dir"`
 }
 
 const (
 	DefaultFrameInterval         = 66       // 默认帧间隔 66ms (~15fps)
 	DefaultMaxDelayFrames        = 500 / 66 // 默认最大延迟帧500ms
 	DefaultMaxClientsPerRoom     = 8        // 默认每个房间最大人数 8 人
+	DefaultMaxSpectatorsPerRoom  = 16       // 默认每个房间最多 16 名观战者
+	DefaultSpectatorDelayFrames  = 150      // 默认观战延迟 150 帧 (66ms 下约 10s)
 	DefaultDeterministicLockstep = -1       // 默认乐观锁步
+	DefaultDeterministicTimeout  = 200      // 默认悲观锁步等待输入超时 200ms
+	DefaultReconnectGracePeriod  = 30000    // 默认断线等待重连 30s
+	DefaultFrameHistorySize      = 4096     // 默认最多保留 4096 帧 (66ms 下约 4.5 分钟)
+	DefaultSnapshotHistorySize   = 4        // 默认最多保留 4 个快照
+	DefaultSnapshotInterval      = 300      // 默认每 300 帧生成一次快照 (66ms 下约 20s)
+	DefaultMaxChunksPerClient    = 64       // 默认每个客户端最多订阅 64 个 chunk
+	DefaultMaxFramesPerDatagram  = 32       // 默认每个数据报最多 32 帧
+	DefaultMaxDatagramBytes      = 1000     // 默认每个数据报最多 1000 字节帧数据
+	DefaultMaxFrameRedundancy    = 8        // 默认每帧最多冗余发送 8 次
+	DefaultSendQueueSize         = 256      // 默认每个客户端最多排队 256 个数据报
+	DefaultSendQueueSaturation   = 5000     // 默认出站队列持续饱和 5s 后断开
+	DefaultReadyQuorumPercent    = 50       // 默认半数玩家准备后开始倒计时
+	DefaultReadyCountdown        = 30000    // 默认准备倒计时 30s
+	DefaultLoadingTimeout        = 60000    // 默认加载超时 60s
+	DefaultPauseVotePercent      = 50       // 默认半数在线玩家同意后暂停或恢复
+	DefaultMaxPauseBudget        = 120000   // 默认每名玩家每场对局最多暂停 2min
+	DefaultAutoPauseOnDisconnect = true     // 默认玩家断线时自动暂停
+	DefaultMaxRequestBytes       = 8192     // 默认单个请求最多 8KB
+	DefaultMaxRequestsPerSecond  = 120      // 默认每个客户端每秒最多 120 个请求
+	DefaultMaxInputBytesPerFrame = 1024     // 默认每帧输入最多 1KB
+	DefaultMaxInputFramesAhead   = 150      // 默认输入最多领先 150 帧 (66ms 下约 10s)
+	DefaultMaxViolations         = 20       // 默认 10s 内违规超过 20 次踢出
+)
+
+// ReaperConfig 空闲房间回收配置
+// 所有时间单位为秒，0 为不回收
+type ReaperConfig struct {
+	// 扫描房间的间隔
+	ReapInterval *uint32 `toml:"reap_interval"`
+
+	// 没有在线玩家的房间在空闲多久后被回收
+	EmptyRoomTimeout *uint32 `toml:"empty_room_timeout"`
+
+	// 各阶段的房间在空闲多久后被回收
+	LobbyIdleTimeout     *uint32 `toml:"lobby_idle_timeout"`
+	PreparingIdleTimeout *uint32 `toml:"preparing_idle_timeout"`
+	LoadingIdleTimeout   *uint32 `toml:"loading_idle_timeout"`
+	InGameIdleTimeout    *uint32 `toml:"ingame_idle_timeout"`
+	PostGameIdleTimeout  *uint32 `toml:"postgame_idle_timeout"`
+}
+
+const (
+	DefaultReapInterval         = 10   // 默认每 10s 扫描一次
+	DefaultEmptyRoomTimeout     = 60   // 默认空房间 1min 后回收
+	DefaultLobbyIdleTimeout     = 1800 // 默认大厅空闲 30min 后回收
+	DefaultPreparingIdleTimeout = 600  // 默认准备阶段空闲 10min 后回收
+	DefaultLoadingIdleTimeout   = 300  // 默认加载阶段空闲 5min 后回收
+	DefaultInGameIdleTimeout    = 300  // 默认游戏中空闲 5min 后回收
+	DefaultPostGameIdleTimeout  = 600  // 默认结算阶段空闲 10min 后回收
 )
 
 type GeneralConfig struct {
 	ServerConfig   `toml:"server"`
 	LockstepConfig `toml:"lockstep"`
+	ReaperConfig   `toml:"reaper"`
 }
 
 func Uint32Ptr(v uint32) *uint32 {
@@ -68,6 +217,10 @@ func StringPtr(v string) *string {
 	return &v
 }
 
+func BoolPtr(v bool) *bool {
+	return &v
+}
+
 func (c *GeneralConfig) ApplyDefaults() {
 	if c.FrameInterval == nil {
 		c.FrameInterval = Uint32Ptr(DefaultFrameInterval)
@@ -78,9 +231,106 @@ func (c *GeneralConfig) ApplyDefaults() {
 	if c.MaxClientsPerRoom == nil {
 		c.MaxClientsPerRoom = Uint16Ptr(DefaultMaxClientsPerRoom)
 	}
+	if c.MaxSpectatorsPerRoom == nil {
+		c.MaxSpectatorsPerRoom = Uint16Ptr(DefaultMaxSpectatorsPerRoom)
+	}
+	if c.SpectatorDelayFrames == nil {
+		c.SpectatorDelayFrames = Uint32Ptr(DefaultSpectatorDelayFrames)
+	}
 	if c.DeterministicLockstep == nil {
 		c.DeterministicLockstep = Int32Ptr(DefaultDeterministicLockstep)
 	}
+	if c.DeterministicTimeout == nil {
+		c.DeterministicTimeout = Uint32Ptr(DefaultDeterministicTimeout)
+	}
+	if c.ReconnectGracePeriod == nil {
+		c.ReconnectGracePeriod = Uint32Ptr(DefaultReconnectGracePeriod)
+	}
+	if c.FrameHistorySize == nil {
+		c.FrameHistorySize = Uint32Ptr(DefaultFrameHistorySize)
+	}
+	if c.SnapshotHistorySize == nil {
+		c.SnapshotHistorySize = Uint32Ptr(DefaultSnapshotHistorySize)
+	}
+	if c.SnapshotInterval == nil {
+		c.SnapshotInterval = Uint32Ptr(DefaultSnapshotInterval)
+	}
+	if c.MaxFramesPerDatagram == nil {
+		c.MaxFramesPerDatagram = Uint16Ptr(DefaultMaxFramesPerDatagram)
+	}
+	if c.MaxDatagramBytes == nil {
+		c.MaxDatagramBytes = Uint32Ptr(DefaultMaxDatagramBytes)
+	}
+	if c.MaxFrameRedundancy == nil {
+		c.MaxFrameRedundancy = Uint16Ptr(DefaultMaxFrameRedundancy)
+	}
+	if c.SendQueueSize == nil {
+		c.SendQueueSize = Uint16Ptr(DefaultSendQueueSize)
+	}
+	if c.SendQueueSaturationTimeout == nil {
+		c.SendQueueSaturationTimeout = Uint32Ptr(DefaultSendQueueSaturation)
+	}
+	if c.ReadyQuorumPercent == nil {
+		c.ReadyQuorumPercent = Uint16Ptr(DefaultReadyQuorumPercent)
+	}
+	if c.ReadyCountdown == nil {
+		c.ReadyCountdown = Uint32Ptr(DefaultReadyCountdown)
+	}
+	if c.LoadingTimeout == nil {
+		c.LoadingTimeout = Uint32Ptr(DefaultLoadingTimeout)
+	}
+	if c.PauseVotePercent == nil {
+		c.PauseVotePercent = Uint16Ptr(DefaultPauseVotePercent)
+	}
+	if c.MaxPauseBudget == nil {
+		c.MaxPauseBudget = Uint32Ptr(DefaultMaxPauseBudget)
+	}
+	if c.AutoPauseOnDisconnect == nil {
+		c.AutoPauseOnDisconnect = BoolPtr(DefaultAutoPauseOnDisconnect)
+	}
+	if c.MaxRequestBytes == nil {
+		c.MaxRequestBytes = Uint32Ptr(DefaultMaxRequestBytes)
+	}
+	if c.MaxRequestsPerSecond == nil {
+		c.MaxRequestsPerSecond = Uint16Ptr(DefaultMaxRequestsPerSecond)
+	}
+	if c.MaxInputBytesPerFrame == nil {
+		c.MaxInputBytesPerFrame = Uint32Ptr(DefaultMaxInputBytesPerFrame)
+	}
+	if c.MaxInputFramesAhead == nil {
+		c.MaxInputFramesAhead = Uint32Ptr(DefaultMaxInputFramesAhead)
+	}
+	if c.MaxViolations == nil {
+		c.MaxViolations = Uint16Ptr(DefaultMaxViolations)
+	}
+	if c.MaxChunksPerClient == nil {
+		c.MaxChunksPerClient = Uint16Ptr(DefaultMaxChunksPerClient)
+	}
+	if c.ReplayDir == nil {
+		c.ReplayDir = StringPtr("")
+	}
+
+	if c.ReapInterval == nil {
+		c.ReapInterval = Uint32Ptr(DefaultReapInterval)
+	}
+	if c.EmptyRoomTimeout == nil {
+		c.EmptyRoomTimeout = Uint32Ptr(DefaultEmptyRoomTimeout)
+	}
+	if c.LobbyIdleTimeout == nil {
+		c.LobbyIdleTimeout = Uint32Ptr(DefaultLobbyIdleTimeout)
+	}
+	if c.PreparingIdleTimeout == nil {
+		c.PreparingIdleTimeout = Uint32Ptr(DefaultPreparingIdleTimeout)
+	}
+	if c.LoadingIdleTimeout == nil {
+		c.LoadingIdleTimeout = Uint32Ptr(DefaultLoadingIdleTimeout)
+	}
+	if c.InGameIdleTimeout == nil {
+		c.InGameIdleTimeout = Uint32Ptr(DefaultInGameIdleTimeout)
+	}
+	if c.PostGameIdleTimeout == nil {
+		c.PostGameIdleTimeout = Uint32Ptr(DefaultPostGameIdleTimeout)
+	}
 
 	if c.Host == nil {
 		c.Host = StringPtr(DefaultHost)
@@ -91,6 +341,9 @@ func (c *GeneralConfig) ApplyDefaults() {
 	if c.GrpcPort == nil {
 		c.GrpcPort = Uint16Ptr(DefaultGrpcPort)
 	}
+	if c.ShutdownDrainTimeout == nil {
+		c.ShutdownDrainTimeout = Uint32Ptr(DefaultShutdownDrainTimeout)
+	}
 }
 
 // RuntimeConfig 包含运行时的配置信息
@@ -35,6 +35,29 @@ func (s Stage) ForwardStage() Stage {
 	}
 }
 
+// stageTransitions 各阶段允许切换到的阶段
+// 任意阶段都可以进入 STAGE_CLOSED
+var stageTransitions = map[Stage][]Stage{
+	STAGE_InLobby:   {STAGE_Preparing},
+	STAGE_Preparing: {STAGE_InLobby, STAGE_Loading},
+	STAGE_Loading:   {STAGE_InGame},
+	STAGE_InGame:    {STAGE_PostGame},
+	STAGE_PostGame:  {STAGE_InLobby},
+}
+
+// CanTransitionTo 判断能否从当前阶段切换到目标阶段。
+func (s Stage) CanTransitionTo(target Stage) bool {
+	if target == STAGE_CLOSED {
+		return true
+	}
+	for _, next := range stageTransitions[s] {
+		if next == target {
+			return true
+		}
+	}
+	return false
+}
+
 // IsLaterThanOrEqual 判断当前阶段是否晚于或等于目标阶段。
 func (s Stage) IsLaterThanOrEqual(target Stage) bool {
 	return s >= target
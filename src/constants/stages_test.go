@@ -0,0 +1,29 @@
+package constants
+
+import "testing"
+
+func TestCanTransitionTo(t *testing.T) {
+	cases := []struct {
+		from, to Stage
+		want     bool
+	}{
+		{STAGE_InLobby, STAGE_Preparing, true},
+		{STAGE_InLobby, STAGE_InGame, false},
+		{STAGE_Preparing, STAGE_InLobby, true},
+		{STAGE_Preparing, STAGE_Loading, true},
+		{STAGE_Loading, STAGE_InGame, true},
+		{STAGE_Loading, STAGE_InLobby, false},
+		{STAGE_InGame, STAGE_PostGame, true},
+		{STAGE_InGame, STAGE_InGame, false},
+		{STAGE_PostGame, STAGE_InLobby, true},
+		{STAGE_PostGame, STAGE_Preparing, false},
+		{STAGE_InGame, STAGE_CLOSED, true},
+		{STAGE_CLOSED, STAGE_InLobby, false},
+		{STAGE_Error, STAGE_CLOSED, true},
+	}
+	for _, tc := range cases {
+		if got := tc.from.CanTransitionTo(tc.to); got != tc.want {
+			t.Errorf("%#x -> %#x: CanTransitionTo = %v, want %v", uint32(tc.from), uint32(tc.to), got, tc.want)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"lockstep-core/src/config"
+	"lockstep-core/src/constants"
 	"lockstep-core/src/internal/server/logic"
 	"lockstep-core/src/messages"
 	"lockstep-core/src/pkg/lockstep/room"
@@ -10,6 +14,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -34,9 +39,21 @@ func NewHTTPHandlers(
 	}
 }
 
-// ListRoomsHandler 处理获取房间列表的请求 (GET /rooms)
+// ListRoomsHandler 处理获取房间列表的请求
+// (GET /rooms?stage={stage}&has_free_slots={true|false}&offset={n}&limit={n})
+// stage 支持十进制或 0x 前缀的十六进制，例如 stage=0x20 仅列出大厅中的房间
 func (h *Serverandlers) ListRoomsHandler(w http.ResponseWriter, r *http.Request) {
-	resp := h.roomService.ListRooms()
+	filter, err := parseListRoomsFilter(r)
+	if err != nil {
+		errResp := &messages.ErrorResponse{
+			Error: fmt.Sprintf("Invalid query parameter: %v", err),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errResp)
+		return
+	}
+	resp := h.roomService.ListRooms(filter)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -49,6 +66,85 @@ func (h *Serverandlers) ListRoomsHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// parseListRoomsFilter 从查询参数中解析房间列表的过滤与分页条件
+func parseListRoomsFilter(r *http.Request) (logic.ListRoomsFilter, error) {
+	var filter logic.ListRoomsFilter
+	queryParams := r.URL.Query()
+
+	if v := queryParams.Get("stage"); v != "" {
+		stage, err := strconv.ParseUint(v, 0, 32)
+		if err != nil {
+			return filter, fmt.Errorf("stage: %w", err)
+		}
+		s := constants.Stage(stage)
+		filter.Stage = &s
+	}
+	if v := queryParams.Get("has_free_slots"); v != "" {
+		hasFreeSlots, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("has_free_slots: %w", err)
+		}
+		filter.HasFreeSlots = hasFreeSlots
+	}
+	if v := queryParams.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return filter, fmt.Errorf("offset must be a non-negative integer")
+		}
+		filter.Offset = offset
+	}
+	if v := queryParams.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return filter, fmt.Errorf("limit must be a non-negative integer")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// RoomDetailHandler 处理获取单个房间详情的请求 (GET /rooms/{id})
+func (h *Serverandlers) RoomDetailHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	roomIDNum, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil || roomIDNum == 0 {
+		errResp := &messages.ErrorResponse{
+			Error: "Invalid room id",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errResp)
+		return
+	}
+
+	resp, ok := h.roomService.GetRoom(uint32(roomIDNum))
+	if !ok {
+		errResp := &messages.ErrorResponse{
+			Error: fmt.Sprintf("room %d not found", roomIDNum),
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errResp)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 // CreateRoomHandler 处理创建房间的请求 (POST /rooms)
 func (h *Serverandlers) CreateRoomHandler(w http.ResponseWriter, r *http.Request) {
 	var reqBody messages.CreateRoomRequest
@@ -95,19 +191,27 @@ func (h *Serverandlers) RoomsHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		h.ListRoomsHandler(w, r)
 	case http.MethodPost:
+		if h.rejectIfShuttingDown(w) {
+			return
+		}
 		h.CreateRoomHandler(w, r)
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
-// JoinRoomHandler 处理加入房间的请求 (WebTransport /join?roomid={roomID}&key={value}&wt={true|false})
+// JoinRoomHandler 处理加入房间的请求 (WebTransport /join?roomid={roomID}&key={value}&wt={true|false}&token={reconnectToken}&spectator={true|false})
+// 携带 token 时为断线重连，恢复玩家原有的席位
+// spectator=true 时以观战者身份加入，不占用玩家席位
 func (h *Serverandlers) JoinRoomHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	log.Printf("JoinRoomHandler called with path: %s", r.URL.Path)
+	if h.rejectIfShuttingDown(w) {
+		return
+	}
 	queryParams := r.URL.Query()
 	roomID := queryParams.Get("roomid")
 	if roomID == "" {
@@ -143,10 +247,17 @@ func (h *Serverandlers) JoinRoomHandler(w http.ResponseWriter, r *http.Request)
 
 	// 获取可选密钥参数
 	key := queryParams.Get("key") // 可选密钥参数
+	// 获取可选重连令牌参数，来自 ResponseJoinSuccess.ReconnectToken
+	token := queryParams.Get("token")
+
+	// 是否以观战者身份加入
+	isSpectator := queryParams.Get("spectator") == "true"
 
 	joinReq := &logic.JoinRoomRequest{
-		RoomID: uint32(roomIDNum),
-		Key:    key,
+		RoomID:         uint32(roomIDNum),
+		Key:            key,
+		ReconnectToken: token,
+		Spectator:      isSpectator,
 	}
 
 	// validate first
@@ -193,7 +304,12 @@ func (h *Serverandlers) JoinRoomHandler(w http.ResponseWriter, r *http.Request)
 		session_impl = session.NewWebsocketSession(wsConn)
 	}
 
-	resp, err := logic.JoinRoom(room, session_impl, joinReq.ReconnectToken)
+	var resp *logic.JoinRoomResponse
+	if joinReq.Spectator {
+		resp, err = logic.JoinRoomAsSpectator(room, session_impl)
+	} else {
+		resp, err = logic.JoinRoom(room, session_impl, joinReq.ReconnectToken)
+	}
 	if err != nil {
 		session_impl.Close()
 		errResp := &messages.ErrorResponse{
@@ -222,10 +338,25 @@ func (h *Serverandlers) HealthCheckHandler(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// rejectIfShuttingDown 服务器关闭期间以 503 拒绝创建房间与加入房间的请求
+func (h *Serverandlers) rejectIfShuttingDown(w http.ResponseWriter) bool {
+	if !h.roomManager.IsShuttingDown() {
+		return false
+	}
+	errResp := &messages.ErrorResponse{
+		Error: "Server is shutting down",
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(errResp)
+	return true
+}
+
 // RegisterHandlers 注册所有 HTTP 处理器
 func (h *Serverandlers) RegisterHandlers() {
 	h.wtServer.RegisterHandler("/", h.HealthCheckHandler)
 	h.wtServer.RegisterHandler("/rooms", h.RoomsHandler)
+	h.wtServer.RegisterHandler("/rooms/{id}", h.RoomDetailHandler)
 	h.wtServer.RegisterHandler("/join", h.JoinRoomHandler)
 }
 
@@ -233,3 +364,31 @@ func (h *Serverandlers) RegisterHandlers() {
 func (h *Serverandlers) Start() error {
 	return h.wtServer.Start()
 }
+
+// shutdownGracePeriod 在房间排空时间之外，留给关闭房间连接与监听器的时间
+const shutdownGracePeriod = 10 * time.Second
+
+// ShutdownTimeout 完整关闭服务器所需的建议超时时间
+func (h *Serverandlers) ShutdownTimeout() time.Duration {
+	drain := time.Duration(config.DefaultShutdownDrainTimeout) * time.Second
+	if h.wtServer.config.ShutdownDrainTimeout != nil {
+		drain = time.Duration(*h.wtServer.config.ShutdownDrainTimeout) * time.Second
+	}
+	return drain + shutdownGracePeriod
+}
+
+// listenerShutdownTimeout 关闭监听器的超时时间，与排空房间所用的 ctx 无关
+const listenerShutdownTimeout = 5 * time.Second
+
+// Shutdown 优雅关闭服务器
+// 先停止接受新房间与新玩家并排空所有房间，再关闭 HTTP/1.1 与 HTTP/3 监听器
+// 排空房间可能用尽 ctx，监听器总是以独立的超时关闭
+func (h *Serverandlers) Shutdown(ctx context.Context) error {
+	drainErr := h.roomManager.Shutdown(ctx)
+	if drainErr != nil {
+		log.Printf("🔴 Error draining rooms: %v", drainErr)
+	}
+	listenerCtx, cancel := context.WithTimeout(context.Background(), listenerShutdownTimeout)
+	defer cancel()
+	return errors.Join(drainErr, h.wtServer.Shutdown(listenerCtx))
+}
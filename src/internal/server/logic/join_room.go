@@ -14,6 +14,7 @@ type JoinRoomRequest struct {
 	RoomID         uint32
 	Key            string // 可选密钥参数
 	ReconnectToken string // 可选重连令牌
+	Spectator      bool   // 是否以观战者身份加入
 }
 
 // JoinRoomResponse 包含加入房间的结果
@@ -45,6 +46,23 @@ func ValidateJoinRoom(
 		return nil, http.StatusNotFound, fmt.Errorf("room %d not found", req.RoomID)
 	}
 
+	// 观战请求，观战者不占用玩家席位，不支持重连令牌
+	if req.Spectator {
+		if req.ReconnectToken != "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("spectators cannot reconnect with token")
+		}
+		if r.MaxSpectators() == 0 {
+			return nil, http.StatusForbidden, fmt.Errorf("room %d does not allow spectators", req.RoomID)
+		}
+		if r.IsSpectatorFull() {
+			return nil, http.StatusConflict, fmt.Errorf("spectator slots of room %d are full", req.RoomID)
+		}
+		if r.HasKey() && !r.CheckKeyCorrect(req.Key) {
+			return nil, http.StatusUnauthorized, fmt.Errorf("invalid key for room %d", req.RoomID)
+		}
+		return r, http.StatusOK, nil
+	}
+
 	// 重连请求
 	if req.ReconnectToken != "" {
 		parsed, ok := r.JwtService.ParseToken(req.ReconnectToken)
@@ -53,27 +71,31 @@ func ValidateJoinRoom(
 		} else if parsed.RoomID != req.RoomID {
 			return nil, http.StatusUnauthorized, fmt.Errorf("reconnect token roomID mismatch for room %d", req.RoomID)
 		} else {
-			user, _ := r.ClientsContainer.Clients.Load(parsed.UserID)
-			// 如果没有找到用户，说明unregister了，不需要在意
-			if user.Session.IsConnected() {
-				return nil, http.StatusConflict, fmt.Errorf("user %d already connected in room %d", parsed.UserID, req.RoomID)
+			user, ok := r.ClientsContainer.Clients.Load(parsed.UserID)
+			// 如果没有找到用户，说明等待重连已超时或已被移出房间
+			// 用户仍在线时，新连接会顶替旧连接（重复登录）
+			if !ok || user == nil {
+				return nil, http.StatusGone, fmt.Errorf("user %d is no longer in room %d", parsed.UserID, req.RoomID)
 			} else {
 				isReconnect = true
 			}
 		}
 	}
 
-	// 检查房间是否满员
-	if r.IsRoomFull() {
-		return nil, http.StatusConflict, fmt.Errorf("room %d is full", req.RoomID)
-	}
+	// 重连玩家的席位仍被保留，且已通过令牌鉴权
+	if !isReconnect {
+		// 检查房间是否满员
+		if r.IsRoomFull() {
+			return nil, http.StatusConflict, fmt.Errorf("room %d is full", req.RoomID)
+		}
 
-	// 鉴权检查
-	// 根据 req.Key 验证密钥是否正确
-	// 如果 Key 不匹配，返回错误
-	if r.HasKey() {
-		if !r.CheckKeyCorrect(req.Key) {
-			return nil, http.StatusUnauthorized, fmt.Errorf("invalid key for room %d", req.RoomID)
+		// 鉴权检查
+		// 根据 req.Key 验证密钥是否正确
+		// 如果 Key 不匹配，返回错误
+		if r.HasKey() {
+			if !r.CheckKeyCorrect(req.Key) {
+				return nil, http.StatusUnauthorized, fmt.Errorf("invalid key for room %d", req.RoomID)
+			}
 		}
 	}
 
@@ -89,6 +111,7 @@ func ValidateJoinRoom(
 
 // JoinRoom 是一个泛型方法，处理玩家加入房间的通用逻辑
 // 支持任何实现了 session.ISession 接口的会话类型
+// reconnectToken 非空时，恢复该令牌对应玩家断线前的席位
 func JoinRoom(
 	r *room.Room,
 	sessionImpl session.ISession,
@@ -98,7 +121,10 @@ func JoinRoom(
 	var err error
 	var isReconnect bool = false
 	if reconnectToken != "" {
-		parse, _ := r.JwtService.ParseToken(reconnectToken)
+		parse, ok := r.JwtService.ParseToken(reconnectToken)
+		if !ok {
+			return nil, fmt.Errorf("invalid reconnect token")
+		}
 		nextUserId = parse.UserID
 		isReconnect = true
 	} else {
@@ -116,11 +142,14 @@ func JoinRoom(
 	log.Printf("Player joining room %d (user ID: %d)", r.ID, nextUserId)
 
 	// 将玩家添加到房间（这会发送到 register channel）
-	r.RegisterPlayer(playerClient)
-
-	// 在独立的 goroutine 中处理此玩家的会话
-	// 这会阻塞直到连接关闭
-	go r.StartServeClient(playerClient)
+	// 房间完成注册后会在独立的 goroutine 中处理此玩家的会话，
+	// 重连玩家的新会话会被换入其原有的 Client
+	if err := r.RegisterPlayer(playerClient); err != nil {
+		if !isReconnect {
+			r.FreeUserID(nextUserId)
+		}
+		return nil, fmt.Errorf("failed to register player: %w", err)
+	}
 
 	return &JoinRoomResponse{
 		UserID:     nextUserId,
@@ -128,3 +157,31 @@ func JoinRoom(
 		ClientInfo: playerClient,
 	}, nil
 }
+
+// JoinRoomAsSpectator 处理观战者加入房间的逻辑
+// 观战者使用独立的 ID 空间，只接收延迟后的帧，不能提交输入
+func JoinRoomAsSpectator(
+	r *room.Room,
+	sessionImpl session.ISession,
+) (*JoinRoomResponse, error) {
+	spectatorID, err := r.Spectators.GetNextUserID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next spectator ID: %w", err)
+	}
+
+	spectator := client.NewClient(spectatorID, sessionImpl, r.GetIncomingMessagesChan())
+	spectator.IsSpectator = true
+
+	log.Printf("Spectator joining room %d (spectator ID: %d)", r.ID, spectatorID)
+
+	if err := r.RegisterPlayer(spectator); err != nil {
+		r.Spectators.FreeUserID(spectatorID)
+		return nil, fmt.Errorf("failed to register spectator: %w", err)
+	}
+
+	return &JoinRoomResponse{
+		UserID:     spectatorID,
+		RoomID:     r.ID,
+		ClientInfo: spectator,
+	}, nil
+}
@@ -3,8 +3,11 @@ package logic
 import (
 	"crypto/sha256"
 	"fmt"
+	"lockstep-core/src/constants"
 	"lockstep-core/src/messages"
 	"lockstep-core/src/pkg/lockstep/room"
+	"lockstep-core/src/types"
+	"slices"
 )
 
 // RoomService 包含所有房间相关的业务逻辑
@@ -19,13 +22,88 @@ func NewRoomService(roomManager room.IRoomManager) *RoomService {
 	}
 }
 
-// ListRooms 获取所有房间列表
-// 输入: 无
-// 输出: ListRoomsResponse proto 消息
-func (s *RoomService) ListRooms() *messages.ListRoomsResponse {
+const (
+	DefaultListRoomsLimit = 50  // 默认每页房间数量
+	MaxListRoomsLimit     = 200 // 每页房间数量上限
+)
+
+// ListRoomsFilter 房间列表的过滤与分页条件
+type ListRoomsFilter struct {
+	Stage        *constants.Stage // 仅列出处于该阶段的房间，nil 为不限
+	HasFreeSlots bool             // 仅列出还有空余席位的房间
+	Offset       int
+	Limit        int // 0 为默认值 DefaultListRoomsLimit
+}
+
+// ListRooms 获取房间列表
+// 输入: ListRoomsFilter 过滤与分页条件
+// 输出: ListRoomsResponse proto 消息，房间按 ID 升序排列
+func (s *RoomService) ListRooms(filter ListRoomsFilter) *messages.ListRoomsResponse {
 	roomIDs := s.roomManager.ListRooms()
-	return &messages.ListRoomsResponse{
-		Rooms: roomIDs,
+	slices.Sort(roomIDs)
+
+	matched := make([]types.RoomInfo, 0, len(roomIDs))
+	for _, id := range roomIDs {
+		r, ok := s.roomManager.GetRoom(id)
+		if !ok {
+			continue
+		}
+		info := r.Info()
+		if filter.Stage != nil && info.GameState != *filter.Stage {
+			continue
+		}
+		if filter.HasFreeSlots && !info.HasFreeSlots() {
+			continue
+		}
+		matched = append(matched, info)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultListRoomsLimit
+	}
+	limit = min(limit, MaxListRoomsLimit)
+	start := min(max(filter.Offset, 0), len(matched))
+	end := min(start+limit, len(matched))
+
+	resp := &messages.ListRoomsResponse{
+		Rooms:   make([]uint32, 0, end-start),
+		Details: make([]*messages.RoomSummary, 0, end-start),
+		Total:   uint32(len(matched)),
+	}
+	for i := start; i < end; i++ {
+		resp.Rooms = append(resp.Rooms, matched[i].RoomID)
+		resp.Details = append(resp.Details, toRoomSummary(&matched[i]))
+	}
+	return resp
+}
+
+// GetRoom 获取单个房间的详情
+// 输入: 房间 ID
+// 输出: RoomSummary proto 消息，房间不存在时返回 false
+func (s *RoomService) GetRoom(roomID uint32) (*messages.RoomSummary, bool) {
+	r, ok := s.roomManager.GetRoom(roomID)
+	if !ok {
+		return nil, false
+	}
+	info := r.Info()
+	return toRoomSummary(&info), true
+}
+
+// toRoomSummary 将房间信息转换为 proto 消息
+func toRoomSummary(info *types.RoomInfo) *messages.RoomSummary {
+	return &messages.RoomSummary{
+		RoomId:         info.RoomID,
+		Name:           info.Name,
+		Stage:          uint32(info.GameState),
+		PlayerCount:    int32(info.PlayerCount),
+		MaxPlayers:     int32(info.MaxPlayers),
+		NeedKey:        info.NeedKey,
+		Metadata:       info.Metadata,
+		OwnerId:        info.OwnerID,
+		SpectatorCount: int32(info.SpectatorCount),
+		MaxSpectators:  int32(info.MaxSpectators),
+		Replay:         info.Replay,
 	}
 }
 
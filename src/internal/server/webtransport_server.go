@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"lockstep-core/src/config"
 	"log"
 	"net/http"
@@ -85,21 +86,25 @@ func (s *ServerCore) Start() error {
 }
 
 // Shutdown 优雅关闭服务器
+// 无论其中一个是否失败，都会尝试关闭 HTTP/1.1 与 HTTP/3 服务器
 func (s *ServerCore) Shutdown(ctx context.Context) error {
 	log.Printf("Shutting down servers...")
 
 	// 关闭 HTTP/1.1 服务器
-	if err := s.httpServer.Shutdown(ctx); err != nil {
-		log.Printf("Error shutting down HTTP/1.1 server: %v", err)
-		return err
+	httpErr := s.httpServer.Shutdown(ctx)
+	if httpErr != nil {
+		log.Printf("Error shutting down HTTP/1.1 server: %v", httpErr)
 	}
 
 	// 关闭 HTTP/3 服务器
-	if err := s.wtServer.Close(); err != nil {
-		log.Printf("Error shutting down HTTP/3 server: %v", err)
-		return err
+	wtErr := s.wtServer.Close()
+	if wtErr != nil {
+		log.Printf("Error shutting down HTTP/3 server: %v", wtErr)
 	}
 
+	if err := errors.Join(httpErr, wtErr); err != nil {
+		return err
+	}
 	log.Printf("Servers shut down successfully")
 	return nil
 }
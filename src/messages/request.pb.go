@@ -26,18 +26,146 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 房间概要信息
+// 用于房间列表与房间详情，客户端无需加入房间即可了解房间状况
+type RoomSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RoomId         uint32                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`                         // 房间ID
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                            // 房间名称
+	Stage          uint32                 `protobuf:"varint,3,opt,name=stage,proto3" json:"stage,omitempty"`                                         // 房间当前阶段，取值同 ResponseStageChange.NewStage
+	PlayerCount    int32                  `protobuf:"varint,4,opt,name=player_count,json=playerCount,proto3" json:"player_count,omitempty"`          // 当前玩家数量(包含等待重连的玩家)
+	MaxPlayers     int32                  `protobuf:"varint,5,opt,name=max_players,json=maxPlayers,proto3" json:"max_players,omitempty"`             // 最大玩家数量
+	NeedKey        bool                   `protobuf:"varint,6,opt,name=need_key,json=needKey,proto3" json:"need_key,omitempty"`                      // 加入房间是否需要密钥
+	Metadata       []byte                 `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`                                    // 游戏世界提供的附加信息，见 IGameWorld.GetRoomMetadata
+	OwnerId        *uint32                `protobuf:"varint,8,opt,name=owner_id,json=ownerId,proto3,oneof" json:"owner_id,omitempty"`                // 房主的玩家ID，房间内没有玩家时为空
+	SpectatorCount int32                  `protobuf:"varint,9,opt,name=spectator_count,json=spectatorCount,proto3" json:"spectator_count,omitempty"` // 当前观战者数量
+	MaxSpectators  int32                  `protobuf:"varint,10,opt,name=max_spectators,json=maxSpectators,proto3" json:"max_spectators,omitempty"`   // 最大观战者数量，0 为不允许观战
+	Replay         bool                   `protobuf:"varint,11,opt,name=replay,proto3" json:"replay,omitempty"`                                      // 是否为播放回放的只读房间
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RoomSummary) Reset() {
+	*x = RoomSummary{}
+	mi := &file_request_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomSummary) ProtoMessage() {}
+
+func (x *RoomSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_request_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomSummary.ProtoReflect.Descriptor instead.
+func (*RoomSummary) Descriptor() ([]byte, []int) {
+	return file_request_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RoomSummary) GetRoomId() uint32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *RoomSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoomSummary) GetStage() uint32 {
+	if x != nil {
+		return x.Stage
+	}
+	return 0
+}
+
+func (x *RoomSummary) GetPlayerCount() int32 {
+	if x != nil {
+		return x.PlayerCount
+	}
+	return 0
+}
+
+func (x *RoomSummary) GetMaxPlayers() int32 {
+	if x != nil {
+		return x.MaxPlayers
+	}
+	return 0
+}
+
+func (x *RoomSummary) GetNeedKey() bool {
+	if x != nil {
+		return x.NeedKey
+	}
+	return false
+}
+
+func (x *RoomSummary) GetMetadata() []byte {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RoomSummary) GetOwnerId() uint32 {
+	if x != nil && x.OwnerId != nil {
+		return *x.OwnerId
+	}
+	return 0
+}
+
+func (x *RoomSummary) GetSpectatorCount() int32 {
+	if x != nil {
+		return x.SpectatorCount
+	}
+	return 0
+}
+
+func (x *RoomSummary) GetMaxSpectators() int32 {
+	if x != nil {
+		return x.MaxSpectators
+	}
+	return 0
+}
+
+func (x *RoomSummary) GetReplay() bool {
+	if x != nil {
+		return x.Replay
+	}
+	return false
+}
+
 // 列出房间的响应消息
-// 包含所有可用房间的ID列表
+// 支持 GET /rooms?stage={stage}&has_free_slots={true|false}&offset={n}&limit={n} 过滤与分页
 type ListRoomsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Rooms         []uint32               `protobuf:"varint,1,rep,packed,name=rooms,proto3" json:"rooms,omitempty"` // 房间ID列表，每个ID是一个无符号32位整数
+	Rooms         []uint32               `protobuf:"varint,1,rep,packed,name=rooms,proto3" json:"rooms,omitempty"` // 本页的房间ID列表，每个ID是一个无符号32位整数
+	Details       []*RoomSummary         `protobuf:"bytes,2,rep,name=details,proto3" json:"details,omitempty"`     // 本页的房间概要信息，与 rooms 一一对应
+	Total         uint32                 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`        // 过滤后、分页前的房间总数
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListRoomsResponse) Reset() {
 	*x = ListRoomsResponse{}
-	mi := &file_request_proto_msgTypes[0]
+	mi := &file_request_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -49,7 +177,7 @@ func (x *ListRoomsResponse) String() string {
 func (*ListRoomsResponse) ProtoMessage() {}
 
 func (x *ListRoomsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_request_proto_msgTypes[0]
+	mi := &file_request_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -62,7 +190,7 @@ func (x *ListRoomsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRoomsResponse.ProtoReflect.Descriptor instead.
 func (*ListRoomsResponse) Descriptor() ([]byte, []int) {
-	return file_request_proto_rawDescGZIP(), []int{0}
+	return file_request_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *ListRoomsResponse) GetRooms() []uint32 {
@@ -72,6 +200,20 @@ func (x *ListRoomsResponse) GetRooms() []uint32 {
 	return nil
 }
 
+func (x *ListRoomsResponse) GetDetails() []*RoomSummary {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *ListRoomsResponse) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
 // 创建房间的请求消息
 // 客户端发送此消息来创建一个新的房间
 type CreateRoomRequest struct {
@@ -84,7 +226,7 @@ type CreateRoomRequest struct {
 
 func (x *CreateRoomRequest) Reset() {
 	*x = CreateRoomRequest{}
-	mi := &file_request_proto_msgTypes[1]
+	mi := &file_request_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -96,7 +238,7 @@ func (x *CreateRoomRequest) String() string {
 func (*CreateRoomRequest) ProtoMessage() {}
 
 func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_request_proto_msgTypes[1]
+	mi := &file_request_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -109,7 +251,7 @@ func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoomRequest.ProtoReflect.Descriptor instead.
 func (*CreateRoomRequest) Descriptor() ([]byte, []int) {
-	return file_request_proto_rawDescGZIP(), []int{1}
+	return file_request_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateRoomRequest) GetName() string {
@@ -137,7 +279,7 @@ type CreateRoomResponse struct {
 
 func (x *CreateRoomResponse) Reset() {
 	*x = CreateRoomResponse{}
-	mi := &file_request_proto_msgTypes[2]
+	mi := &file_request_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -149,7 +291,7 @@ func (x *CreateRoomResponse) String() string {
 func (*CreateRoomResponse) ProtoMessage() {}
 
 func (x *CreateRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_request_proto_msgTypes[2]
+	mi := &file_request_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -162,7 +304,7 @@ func (x *CreateRoomResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoomResponse.ProtoReflect.Descriptor instead.
 func (*CreateRoomResponse) Descriptor() ([]byte, []int) {
-	return file_request_proto_rawDescGZIP(), []int{2}
+	return file_request_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateRoomResponse) GetRoomId() uint32 {
@@ -183,7 +325,7 @@ type ErrorResponse struct {
 
 func (x *ErrorResponse) Reset() {
 	*x = ErrorResponse{}
-	mi := &file_request_proto_msgTypes[3]
+	mi := &file_request_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -195,7 +337,7 @@ func (x *ErrorResponse) String() string {
 func (*ErrorResponse) ProtoMessage() {}
 
 func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_request_proto_msgTypes[3]
+	mi := &file_request_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,7 +350,7 @@ func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ErrorResponse.ProtoReflect.Descriptor instead.
 func (*ErrorResponse) Descriptor() ([]byte, []int) {
-	return file_request_proto_rawDescGZIP(), []int{3}
+	return file_request_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ErrorResponse) GetError() string {
@@ -231,7 +373,7 @@ type HealthCheckResponse struct {
 
 func (x *HealthCheckResponse) Reset() {
 	*x = HealthCheckResponse{}
-	mi := &file_request_proto_msgTypes[4]
+	mi := &file_request_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -243,7 +385,7 @@ func (x *HealthCheckResponse) String() string {
 func (*HealthCheckResponse) ProtoMessage() {}
 
 func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_request_proto_msgTypes[4]
+	mi := &file_request_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -256,7 +398,7 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
 func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
-	return file_request_proto_rawDescGZIP(), []int{4}
+	return file_request_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *HealthCheckResponse) GetStatus() string {
@@ -284,9 +426,26 @@ var File_request_proto protoreflect.FileDescriptor
 
 const file_request_proto_rawDesc = "" +
 	"\n" +
-	"\rrequest.proto\x12\bmessages\x1a\x1bgoogle/protobuf/empty.proto\")\n" +
+	"\rrequest.proto\x12\bmessages\x1a\x1bgoogle/protobuf/empty.proto\"\xe0\x02\n" +
+	"\vRoomSummary\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\rR\x06roomId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05stage\x18\x03 \x01(\rR\x05stage\x12!\n" +
+	"\fplayer_count\x18\x04 \x01(\x05R\vplayerCount\x12\x1f\n" +
+	"\vmax_players\x18\x05 \x01(\x05R\n" +
+	"maxPlayers\x12\x19\n" +
+	"\bneed_key\x18\x06 \x01(\bR\aneedKey\x12\x1a\n" +
+	"\bmetadata\x18\a \x01(\fR\bmetadata\x12\x1e\n" +
+	"\bowner_id\x18\b \x01(\rH\x00R\aownerId\x88\x01\x01\x12'\n" +
+	"\x0fspectator_count\x18\t \x01(\x05R\x0espectatorCount\x12%\n" +
+	"\x0emax_spectators\x18\n" +
+	" \x01(\x05R\rmaxSpectators\x12\x16\n" +
+	"\x06replay\x18\v \x01(\bR\x06replayB\v\n" +
+	"\t_owner_id\"p\n" +
 	"\x11ListRoomsResponse\x12\x14\n" +
-	"\x05rooms\x18\x01 \x03(\rR\x05rooms\"9\n" +
+	"\x05rooms\x18\x01 \x03(\rR\x05rooms\x12/\n" +
+	"\adetails\x18\x02 \x03(\v2\x15.messages.RoomSummaryR\adetails\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\rR\x05total\"9\n" +
 	"\x11CreateRoomRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\"-\n" +
@@ -316,27 +475,29 @@ func file_request_proto_rawDescGZIP() []byte {
 	return file_request_proto_rawDescData
 }
 
-var file_request_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_request_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_request_proto_goTypes = []any{
-	(*ListRoomsResponse)(nil),   // 0: messages.ListRoomsResponse
-	(*CreateRoomRequest)(nil),   // 1: messages.CreateRoomRequest
-	(*CreateRoomResponse)(nil),  // 2: messages.CreateRoomResponse
-	(*ErrorResponse)(nil),       // 3: messages.ErrorResponse
-	(*HealthCheckResponse)(nil), // 4: messages.HealthCheckResponse
-	(*emptypb.Empty)(nil),       // 5: google.protobuf.Empty
+	(*RoomSummary)(nil),         // 0: messages.RoomSummary
+	(*ListRoomsResponse)(nil),   // 1: messages.ListRoomsResponse
+	(*CreateRoomRequest)(nil),   // 2: messages.CreateRoomRequest
+	(*CreateRoomResponse)(nil),  // 3: messages.CreateRoomResponse
+	(*ErrorResponse)(nil),       // 4: messages.ErrorResponse
+	(*HealthCheckResponse)(nil), // 5: messages.HealthCheckResponse
+	(*emptypb.Empty)(nil),       // 6: google.protobuf.Empty
 }
 var file_request_proto_depIdxs = []int32{
-	5, // 0: messages.LockstepService.ListRooms:input_type -> google.protobuf.Empty
-	1, // 1: messages.LockstepService.CreateRoom:input_type -> messages.CreateRoomRequest
-	5, // 2: messages.LockstepService.HealthCheck:input_type -> google.protobuf.Empty
-	0, // 3: messages.LockstepService.ListRooms:output_type -> messages.ListRoomsResponse
-	2, // 4: messages.LockstepService.CreateRoom:output_type -> messages.CreateRoomResponse
-	4, // 5: messages.LockstepService.HealthCheck:output_type -> messages.HealthCheckResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: messages.ListRoomsResponse.details:type_name -> messages.RoomSummary
+	6, // 1: messages.LockstepService.ListRooms:input_type -> google.protobuf.Empty
+	2, // 2: messages.LockstepService.CreateRoom:input_type -> messages.CreateRoomRequest
+	6, // 3: messages.LockstepService.HealthCheck:input_type -> google.protobuf.Empty
+	1, // 4: messages.LockstepService.ListRooms:output_type -> messages.ListRoomsResponse
+	3, // 5: messages.LockstepService.CreateRoom:output_type -> messages.CreateRoomResponse
+	5, // 6: messages.LockstepService.HealthCheck:output_type -> messages.HealthCheckResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_request_proto_init() }
@@ -344,13 +505,14 @@ func file_request_proto_init() {
 	if File_request_proto != nil {
 		return
 	}
+	file_request_proto_msgTypes[0].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_request_proto_rawDesc), len(file_request_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -39,6 +39,12 @@ type SessionRequest struct {
 	//	*SessionRequest_Other
 	//	*SessionRequest_EndGame
 	//	*SessionRequest_PostGameData
+	//	*SessionRequest_TransferOwner
+	//	*SessionRequest_ReplayControl
+	//	*SessionRequest_SubscribeChunks
+	//	*SessionRequest_UnsubscribeChunks
+	//	*SessionRequest_Pause
+	//	*SessionRequest_Resume
 	Payload       isSessionRequest_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -162,6 +168,60 @@ func (x *SessionRequest) GetPostGameData() *RequestPostGameData {
 	return nil
 }
 
+func (x *SessionRequest) GetTransferOwner() *RequestTransferOwner {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionRequest_TransferOwner); ok {
+			return x.TransferOwner
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetReplayControl() *RequestReplayControl {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionRequest_ReplayControl); ok {
+			return x.ReplayControl
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetSubscribeChunks() *RequestSubscribeChunks {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionRequest_SubscribeChunks); ok {
+			return x.SubscribeChunks
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetUnsubscribeChunks() *RequestUnsubscribeChunks {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionRequest_UnsubscribeChunks); ok {
+			return x.UnsubscribeChunks
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetPause() *RequestPause {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionRequest_Pause); ok {
+			return x.Pause
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetResume() *RequestResume {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionRequest_Resume); ok {
+			return x.Resume
+		}
+	}
+	return nil
+}
+
 type isSessionRequest_Payload interface {
 	isSessionRequest_Payload()
 }
@@ -207,6 +267,34 @@ type SessionRequest_PostGameData struct {
 	PostGameData *RequestPostGameData `protobuf:"bytes,9,opt,name=post_game_data,json=postGameData,proto3,oneof"`
 }
 
+type SessionRequest_TransferOwner struct {
+	// 任意阶段
+	TransferOwner *RequestTransferOwner `protobuf:"bytes,10,opt,name=transfer_owner,json=transferOwner,proto3,oneof"`
+}
+
+type SessionRequest_ReplayControl struct {
+	// 回放房间
+	ReplayControl *RequestReplayControl `protobuf:"bytes,11,opt,name=replay_control,json=replayControl,proto3,oneof"`
+}
+
+type SessionRequest_SubscribeChunks struct {
+	// 分 chunk 的大世界，任意阶段
+	SubscribeChunks *RequestSubscribeChunks `protobuf:"bytes,12,opt,name=subscribe_chunks,json=subscribeChunks,proto3,oneof"`
+}
+
+type SessionRequest_UnsubscribeChunks struct {
+	UnsubscribeChunks *RequestUnsubscribeChunks `protobuf:"bytes,13,opt,name=unsubscribe_chunks,json=unsubscribeChunks,proto3,oneof"`
+}
+
+type SessionRequest_Pause struct {
+	// STAGE_InGame 暂停与恢复
+	Pause *RequestPause `protobuf:"bytes,14,opt,name=pause,proto3,oneof"`
+}
+
+type SessionRequest_Resume struct {
+	Resume *RequestResume `protobuf:"bytes,15,opt,name=resume,proto3,oneof"`
+}
+
 func (*SessionRequest_InLobby) isSessionRequest_Payload() {}
 
 func (*SessionRequest_ToPreparing) isSessionRequest_Payload() {}
@@ -225,6 +313,18 @@ func (*SessionRequest_EndGame) isSessionRequest_Payload() {}
 
 func (*SessionRequest_PostGameData) isSessionRequest_Payload() {}
 
+func (*SessionRequest_TransferOwner) isSessionRequest_Payload() {}
+
+func (*SessionRequest_ReplayControl) isSessionRequest_Payload() {}
+
+func (*SessionRequest_SubscribeChunks) isSessionRequest_Payload() {}
+
+func (*SessionRequest_UnsubscribeChunks) isSessionRequest_Payload() {}
+
+func (*SessionRequest_Pause) isSessionRequest_Payload() {}
+
+func (*SessionRequest_Resume) isSessionRequest_Payload() {}
+
 // RequestInLobby 大厅中的请求，透传给游戏世界
 type RequestInLobby struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -418,9 +518,12 @@ func (x *RequestToInLobby) GetData() []byte {
 }
 
 // Loading 加载阶段
+// 只携带 progress 而 isLoaded 为 false 时为加载进度报告，否则为加载完毕
 type RequestLoaded struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IsLoaded      bool                   `protobuf:"varint,1,opt,name=isLoaded,proto3" json:"isLoaded,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	IsLoaded bool                   `protobuf:"varint,1,opt,name=isLoaded,proto3" json:"isLoaded,omitempty"`
+	// 加载进度百分比，0-100
+	Progress      *uint32 `protobuf:"varint,2,opt,name=progress,proto3,oneof" json:"progress,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -462,6 +565,13 @@ func (x *RequestLoaded) GetIsLoaded() bool {
 	return false
 }
 
+func (x *RequestLoaded) GetProgress() uint32 {
+	if x != nil && x.Progress != nil {
+		return *x.Progress
+	}
+	return 0
+}
+
 // RequestBlank 用于心跳并携带具体用户请求
 type RequestInGameFrames struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -470,8 +580,19 @@ type RequestInGameFrames struct {
 	// 客户端确认服务端上一次的“步进到”的帧ID
 	AckFrameId uint32 `protobuf:"varint,2,opt,name=ack_frame_id,json=ackFrameId,proto3" json:"ack_frame_id,omitempty"`
 	// 携带的bytes，框架将直接传给游戏世界处理
-	// 如果为空，则说明为没有操作空白帧
-	Data          []byte `protobuf:"bytes,3,opt,name=data,proto3,oneof" json:"data,omitempty"`
+	// 未设置时本数据包只用于确认与心跳，不作为输入；设置为空时为没有操作的空白帧
+	// 与 inputs 相同，服务端按玩家与帧号去重，同一帧只接受第一个设置了 data 的数据报
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3,oneof" json:"data,omitempty"`
+	// 可选的客户端状态校验和，用于检测客户端与权威游戏世界不同步
+	// checksum 为客户端步进到达 checksum_frame_id 帧后的状态校验和，算法由游戏自行约定
+	ChecksumFrameId *uint32 `protobuf:"varint,4,opt,name=checksum_frame_id,json=checksumFrameId,proto3,oneof" json:"checksum_frame_id,omitempty"`
+	Checksum        *uint64 `protobuf:"varint,5,opt,name=checksum,proto3,oneof" json:"checksum,omitempty"`
+	// 冗余发送的最近若干个尚未被服务端确认收到的输入，帧号升序
+	// 单个数据报丢失时，其中的输入仍可随后续数据报到达
+	// 服务端按玩家与帧号去重，同一帧的输入只会交给游戏世界一次；
+	// 客户端可丢弃帧号不大于 ResponseInGameFrames.last_input_frame_id 的输入
+	// 设置时 data 字段被忽略，frame_id 仍表示客户端所在的帧
+	Inputs        []*InputEntry `protobuf:"bytes,6,rep,name=inputs,proto3" json:"inputs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -527,6 +648,82 @@ func (x *RequestInGameFrames) GetData() []byte {
 	return nil
 }
 
+func (x *RequestInGameFrames) GetChecksumFrameId() uint32 {
+	if x != nil && x.ChecksumFrameId != nil {
+		return *x.ChecksumFrameId
+	}
+	return 0
+}
+
+func (x *RequestInGameFrames) GetChecksum() uint64 {
+	if x != nil && x.Checksum != nil {
+		return *x.Checksum
+	}
+	return 0
+}
+
+func (x *RequestInGameFrames) GetInputs() []*InputEntry {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+// 某一帧的客户端输入
+type InputEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 输入所属的帧号
+	FrameId uint32 `protobuf:"varint,1,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
+	// 携带的bytes，框架将直接传给游戏世界处理
+	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InputEntry) Reset() {
+	*x = InputEntry{}
+	mi := &file_session_req_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InputEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputEntry) ProtoMessage() {}
+
+func (x *InputEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_session_req_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputEntry.ProtoReflect.Descriptor instead.
+func (*InputEntry) Descriptor() ([]byte, []int) {
+	return file_session_req_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *InputEntry) GetFrameId() uint32 {
+	if x != nil {
+		return x.FrameId
+	}
+	return 0
+}
+
+func (x *InputEntry) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 // 申请结束游戏
 // 如果同意则跳转到 PostGame 阶段
 type RequestEndGame struct {
@@ -541,7 +738,7 @@ type RequestEndGame struct {
 
 func (x *RequestEndGame) Reset() {
 	*x = RequestEndGame{}
-	mi := &file_session_req_proto_msgTypes[7]
+	mi := &file_session_req_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -553,7 +750,7 @@ func (x *RequestEndGame) String() string {
 func (*RequestEndGame) ProtoMessage() {}
 
 func (x *RequestEndGame) ProtoReflect() protoreflect.Message {
-	mi := &file_session_req_proto_msgTypes[7]
+	mi := &file_session_req_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -566,7 +763,7 @@ func (x *RequestEndGame) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestEndGame.ProtoReflect.Descriptor instead.
 func (*RequestEndGame) Descriptor() ([]byte, []int) {
-	return file_session_req_proto_rawDescGZIP(), []int{7}
+	return file_session_req_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *RequestEndGame) GetStatusCode() uint32 {
@@ -595,7 +792,7 @@ type RequestPostGameData struct {
 
 func (x *RequestPostGameData) Reset() {
 	*x = RequestPostGameData{}
-	mi := &file_session_req_proto_msgTypes[8]
+	mi := &file_session_req_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -607,7 +804,7 @@ func (x *RequestPostGameData) String() string {
 func (*RequestPostGameData) ProtoMessage() {}
 
 func (x *RequestPostGameData) ProtoReflect() protoreflect.Message {
-	mi := &file_session_req_proto_msgTypes[8]
+	mi := &file_session_req_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -620,7 +817,7 @@ func (x *RequestPostGameData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestPostGameData.ProtoReflect.Descriptor instead.
 func (*RequestPostGameData) Descriptor() ([]byte, []int) {
-	return file_session_req_proto_rawDescGZIP(), []int{8}
+	return file_session_req_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *RequestPostGameData) GetData() []byte {
@@ -641,7 +838,7 @@ type RequestOther struct {
 
 func (x *RequestOther) Reset() {
 	*x = RequestOther{}
-	mi := &file_session_req_proto_msgTypes[9]
+	mi := &file_session_req_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -653,7 +850,7 @@ func (x *RequestOther) String() string {
 func (*RequestOther) ProtoMessage() {}
 
 func (x *RequestOther) ProtoReflect() protoreflect.Message {
-	mi := &file_session_req_proto_msgTypes[9]
+	mi := &file_session_req_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -666,7 +863,7 @@ func (x *RequestOther) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestOther.ProtoReflect.Descriptor instead.
 func (*RequestOther) Descriptor() ([]byte, []int) {
-	return file_session_req_proto_rawDescGZIP(), []int{9}
+	return file_session_req_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *RequestOther) GetData() []byte {
@@ -676,11 +873,289 @@ func (x *RequestOther) GetData() []byte {
 	return nil
 }
 
+// 房主将房主身份转让给房间内的另一名玩家
+// 仅当前房主可以发起
+type RequestTransferOwner struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NewOwnerId    uint32                 `protobuf:"varint,1,opt,name=new_owner_id,json=newOwnerId,proto3" json:"new_owner_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestTransferOwner) Reset() {
+	*x = RequestTransferOwner{}
+	mi := &file_session_req_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestTransferOwner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestTransferOwner) ProtoMessage() {}
+
+func (x *RequestTransferOwner) ProtoReflect() protoreflect.Message {
+	mi := &file_session_req_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestTransferOwner.ProtoReflect.Descriptor instead.
+func (*RequestTransferOwner) Descriptor() ([]byte, []int) {
+	return file_session_req_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RequestTransferOwner) GetNewOwnerId() uint32 {
+	if x != nil {
+		return x.NewOwnerId
+	}
+	return 0
+}
+
+// 控制回放房间的播放，仅房主可以发起
+// 只设置需要改变的字段，未设置的字段保持不变
+type RequestReplayControl struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 暂停或继续播放
+	Paused *bool `protobuf:"varint,1,opt,name=paused,proto3,oneof" json:"paused,omitempty"`
+	// 跳转到指定帧，即跳转后已步进到达的帧号，0 为回到开头
+	SeekFrameId *uint32 `protobuf:"varint,2,opt,name=seek_frame_id,json=seekFrameId,proto3,oneof" json:"seek_frame_id,omitempty"`
+	// 播放速度倍率，例如 0.5、1、2、4
+	Speed         *float32 `protobuf:"fixed32,3,opt,name=speed,proto3,oneof" json:"speed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestReplayControl) Reset() {
+	*x = RequestReplayControl{}
+	mi := &file_session_req_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestReplayControl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestReplayControl) ProtoMessage() {}
+
+func (x *RequestReplayControl) ProtoReflect() protoreflect.Message {
+	mi := &file_session_req_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestReplayControl.ProtoReflect.Descriptor instead.
+func (*RequestReplayControl) Descriptor() ([]byte, []int) {
+	return file_session_req_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RequestReplayControl) GetPaused() bool {
+	if x != nil && x.Paused != nil {
+		return *x.Paused
+	}
+	return false
+}
+
+func (x *RequestReplayControl) GetSeekFrameId() uint32 {
+	if x != nil && x.SeekFrameId != nil {
+		return *x.SeekFrameId
+	}
+	return 0
+}
+
+func (x *RequestReplayControl) GetSpeed() float32 {
+	if x != nil && x.Speed != nil {
+		return *x.Speed
+	}
+	return 0
+}
+
+// 订阅 chunk，此后服务端会发送这些 chunk 的帧数据
+// 客户端默认只订阅 chunk 0
+type RequestSubscribeChunks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChunkIds      []uint32               `protobuf:"varint,1,rep,packed,name=chunk_ids,json=chunkIds,proto3" json:"chunk_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestSubscribeChunks) Reset() {
+	*x = RequestSubscribeChunks{}
+	mi := &file_session_req_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestSubscribeChunks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestSubscribeChunks) ProtoMessage() {}
+
+func (x *RequestSubscribeChunks) ProtoReflect() protoreflect.Message {
+	mi := &file_session_req_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestSubscribeChunks.ProtoReflect.Descriptor instead.
+func (*RequestSubscribeChunks) Descriptor() ([]byte, []int) {
+	return file_session_req_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RequestSubscribeChunks) GetChunkIds() []uint32 {
+	if x != nil {
+		return x.ChunkIds
+	}
+	return nil
+}
+
+// 取消订阅 chunk，此后不再接收这些 chunk 的帧数据
+type RequestUnsubscribeChunks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChunkIds      []uint32               `protobuf:"varint,1,rep,packed,name=chunk_ids,json=chunkIds,proto3" json:"chunk_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestUnsubscribeChunks) Reset() {
+	*x = RequestUnsubscribeChunks{}
+	mi := &file_session_req_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestUnsubscribeChunks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestUnsubscribeChunks) ProtoMessage() {}
+
+func (x *RequestUnsubscribeChunks) ProtoReflect() protoreflect.Message {
+	mi := &file_session_req_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestUnsubscribeChunks.ProtoReflect.Descriptor instead.
+func (*RequestUnsubscribeChunks) Descriptor() ([]byte, []int) {
+	return file_session_req_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RequestUnsubscribeChunks) GetChunkIds() []uint32 {
+	if x != nil {
+		return x.ChunkIds
+	}
+	return nil
+}
+
+// 请求暂停对局
+// 按房间配置由房主直接暂停，或由在线玩家投票，票数达到要求时暂停
+// 每名玩家每场对局可用的暂停时长有限，用尽后不能再发起暂停
+type RequestPause struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPause) Reset() {
+	*x = RequestPause{}
+	mi := &file_session_req_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPause) ProtoMessage() {}
+
+func (x *RequestPause) ProtoReflect() protoreflect.Message {
+	mi := &file_session_req_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPause.ProtoReflect.Descriptor instead.
+func (*RequestPause) Descriptor() ([]byte, []int) {
+	return file_session_req_proto_rawDescGZIP(), []int{15}
+}
+
+// 请求恢复对局，与暂停采用相同的房主或投票规则
+type RequestResume struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestResume) Reset() {
+	*x = RequestResume{}
+	mi := &file_session_req_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestResume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestResume) ProtoMessage() {}
+
+func (x *RequestResume) ProtoReflect() protoreflect.Message {
+	mi := &file_session_req_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestResume.ProtoReflect.Descriptor instead.
+func (*RequestResume) Descriptor() ([]byte, []int) {
+	return file_session_req_proto_rawDescGZIP(), []int{16}
+}
+
 var File_session_req_proto protoreflect.FileDescriptor
 
 const file_session_req_proto_rawDesc = "" +
 	"\n" +
-	"\x11session_req.proto\x12\bmessages\"\xab\x04\n" +
+	"\x11session_req.proto\x12\bmessages\"\xc4\a\n" +
 	"\x0eSessionRequest\x125\n" +
 	"\bin_lobby\x18\x01 \x01(\v2\x18.messages.RequestInLobbyH\x00R\ainLobby\x12A\n" +
 	"\fto_preparing\x18\x02 \x01(\v2\x1c.messages.RequestToPreparingH\x00R\vtoPreparing\x12.\n" +
@@ -690,7 +1165,14 @@ const file_session_req_proto_rawDesc = "" +
 	"\x0ein_game_frames\x18\x06 \x01(\v2\x1d.messages.RequestInGameFramesH\x00R\finGameFrames\x12.\n" +
 	"\x05other\x18\a \x01(\v2\x16.messages.RequestOtherH\x00R\x05other\x125\n" +
 	"\bend_game\x18\b \x01(\v2\x18.messages.RequestEndGameH\x00R\aendGame\x12E\n" +
-	"\x0epost_game_data\x18\t \x01(\v2\x1d.messages.RequestPostGameDataH\x00R\fpostGameDataB\t\n" +
+	"\x0epost_game_data\x18\t \x01(\v2\x1d.messages.RequestPostGameDataH\x00R\fpostGameData\x12G\n" +
+	"\x0etransfer_owner\x18\n" +
+	" \x01(\v2\x1e.messages.RequestTransferOwnerH\x00R\rtransferOwner\x12G\n" +
+	"\x0ereplay_control\x18\v \x01(\v2\x1e.messages.RequestReplayControlH\x00R\rreplayControl\x12M\n" +
+	"\x10subscribe_chunks\x18\f \x01(\v2 .messages.RequestSubscribeChunksH\x00R\x0fsubscribeChunks\x12S\n" +
+	"\x12unsubscribe_chunks\x18\r \x01(\v2\".messages.RequestUnsubscribeChunksH\x00R\x11unsubscribeChunks\x12.\n" +
+	"\x05pause\x18\x0e \x01(\v2\x16.messages.RequestPauseH\x00R\x05pause\x121\n" +
+	"\x06resume\x18\x0f \x01(\v2\x17.messages.RequestResumeH\x00R\x06resumeB\t\n" +
 	"\apayload\"$\n" +
 	"\x0eRequestInLobby\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\"(\n" +
@@ -701,15 +1183,26 @@ const file_session_req_proto_rawDesc = "" +
 	"\x04data\x18\x02 \x01(\fH\x00R\x04data\x88\x01\x01B\a\n" +
 	"\x05_data\"&\n" +
 	"\x10RequestToInLobby\x12\x12\n" +
-	"\x04data\x18\x01 \x01(\fR\x04data\"+\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"Y\n" +
 	"\rRequestLoaded\x12\x1a\n" +
-	"\bisLoaded\x18\x01 \x01(\bR\bisLoaded\"t\n" +
+	"\bisLoaded\x18\x01 \x01(\bR\bisLoaded\x12\x1f\n" +
+	"\bprogress\x18\x02 \x01(\rH\x00R\bprogress\x88\x01\x01B\v\n" +
+	"\t_progress\"\x97\x02\n" +
 	"\x13RequestInGameFrames\x12\x19\n" +
 	"\bframe_id\x18\x01 \x01(\rR\aframeId\x12 \n" +
 	"\fack_frame_id\x18\x02 \x01(\rR\n" +
 	"ackFrameId\x12\x17\n" +
-	"\x04data\x18\x03 \x01(\fH\x00R\x04data\x88\x01\x01B\a\n" +
-	"\x05_data\"R\n" +
+	"\x04data\x18\x03 \x01(\fH\x00R\x04data\x88\x01\x01\x12/\n" +
+	"\x11checksum_frame_id\x18\x04 \x01(\rH\x01R\x0fchecksumFrameId\x88\x01\x01\x12\x1f\n" +
+	"\bchecksum\x18\x05 \x01(\x04H\x02R\bchecksum\x88\x01\x01\x12,\n" +
+	"\x06inputs\x18\x06 \x03(\v2\x14.messages.InputEntryR\x06inputsB\a\n" +
+	"\x05_dataB\x14\n" +
+	"\x12_checksum_frame_idB\v\n" +
+	"\t_checksum\";\n" +
+	"\n" +
+	"InputEntry\x12\x19\n" +
+	"\bframe_id\x18\x01 \x01(\rR\aframeId\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"R\n" +
 	"\x0eRequestEndGame\x12\x1e\n" +
 	"\n" +
 	"StatusCode\x18\x01 \x01(\rR\n" +
@@ -720,7 +1213,23 @@ const file_session_req_proto_rawDesc = "" +
 	"\x04data\x18\x01 \x01(\fH\x00R\x04data\x88\x01\x01B\a\n" +
 	"\x05_data\"\"\n" +
 	"\fRequestOther\x12\x12\n" +
-	"\x04data\x18\x01 \x01(\fR\x04dataB\rZ\v./;messagesb\x06proto3"
+	"\x04data\x18\x01 \x01(\fR\x04data\"8\n" +
+	"\x14RequestTransferOwner\x12 \n" +
+	"\fnew_owner_id\x18\x01 \x01(\rR\n" +
+	"newOwnerId\"\x9e\x01\n" +
+	"\x14RequestReplayControl\x12\x1b\n" +
+	"\x06paused\x18\x01 \x01(\bH\x00R\x06paused\x88\x01\x01\x12'\n" +
+	"\rseek_frame_id\x18\x02 \x01(\rH\x01R\vseekFrameId\x88\x01\x01\x12\x19\n" +
+	"\x05speed\x18\x03 \x01(\x02H\x02R\x05speed\x88\x01\x01B\t\n" +
+	"\a_pausedB\x10\n" +
+	"\x0e_seek_frame_idB\b\n" +
+	"\x06_speed\"5\n" +
+	"\x16RequestSubscribeChunks\x12\x1b\n" +
+	"\tchunk_ids\x18\x01 \x03(\rR\bchunkIds\"7\n" +
+	"\x18RequestUnsubscribeChunks\x12\x1b\n" +
+	"\tchunk_ids\x18\x01 \x03(\rR\bchunkIds\"\x0e\n" +
+	"\fRequestPause\"\x0f\n" +
+	"\rRequestResumeB\rZ\v./;messagesb\x06proto3"
 
 var (
 	file_session_req_proto_rawDescOnce sync.Once
@@ -734,34 +1243,48 @@ func file_session_req_proto_rawDescGZIP() []byte {
 	return file_session_req_proto_rawDescData
 }
 
-var file_session_req_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_session_req_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_session_req_proto_goTypes = []any{
-	(*SessionRequest)(nil),      // 0: messages.SessionRequest
-	(*RequestInLobby)(nil),      // 1: messages.RequestInLobby
-	(*RequestToPreparing)(nil),  // 2: messages.RequestToPreparing
-	(*RequestReady)(nil),        // 3: messages.RequestReady
-	(*RequestToInLobby)(nil),    // 4: messages.RequestToInLobby
-	(*RequestLoaded)(nil),       // 5: messages.RequestLoaded
-	(*RequestInGameFrames)(nil), // 6: messages.RequestInGameFrames
-	(*RequestEndGame)(nil),      // 7: messages.RequestEndGame
-	(*RequestPostGameData)(nil), // 8: messages.RequestPostGameData
-	(*RequestOther)(nil),        // 9: messages.RequestOther
+	(*SessionRequest)(nil),           // 0: messages.SessionRequest
+	(*RequestInLobby)(nil),           // 1: messages.RequestInLobby
+	(*RequestToPreparing)(nil),       // 2: messages.RequestToPreparing
+	(*RequestReady)(nil),             // 3: messages.RequestReady
+	(*RequestToInLobby)(nil),         // 4: messages.RequestToInLobby
+	(*RequestLoaded)(nil),            // 5: messages.RequestLoaded
+	(*RequestInGameFrames)(nil),      // 6: messages.RequestInGameFrames
+	(*InputEntry)(nil),               // 7: messages.InputEntry
+	(*RequestEndGame)(nil),           // 8: messages.RequestEndGame
+	(*RequestPostGameData)(nil),      // 9: messages.RequestPostGameData
+	(*RequestOther)(nil),             // 10: messages.RequestOther
+	(*RequestTransferOwner)(nil),     // 11: messages.RequestTransferOwner
+	(*RequestReplayControl)(nil),     // 12: messages.RequestReplayControl
+	(*RequestSubscribeChunks)(nil),   // 13: messages.RequestSubscribeChunks
+	(*RequestUnsubscribeChunks)(nil), // 14: messages.RequestUnsubscribeChunks
+	(*RequestPause)(nil),             // 15: messages.RequestPause
+	(*RequestResume)(nil),            // 16: messages.RequestResume
 }
 var file_session_req_proto_depIdxs = []int32{
-	1, // 0: messages.SessionRequest.in_lobby:type_name -> messages.RequestInLobby
-	2, // 1: messages.SessionRequest.to_preparing:type_name -> messages.RequestToPreparing
-	3, // 2: messages.SessionRequest.ready:type_name -> messages.RequestReady
-	4, // 3: messages.SessionRequest.to_in_lobby:type_name -> messages.RequestToInLobby
-	5, // 4: messages.SessionRequest.loaded:type_name -> messages.RequestLoaded
-	6, // 5: messages.SessionRequest.in_game_frames:type_name -> messages.RequestInGameFrames
-	9, // 6: messages.SessionRequest.other:type_name -> messages.RequestOther
-	7, // 7: messages.SessionRequest.end_game:type_name -> messages.RequestEndGame
-	8, // 8: messages.SessionRequest.post_game_data:type_name -> messages.RequestPostGameData
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	1,  // 0: messages.SessionRequest.in_lobby:type_name -> messages.RequestInLobby
+	2,  // 1: messages.SessionRequest.to_preparing:type_name -> messages.RequestToPreparing
+	3,  // 2: messages.SessionRequest.ready:type_name -> messages.RequestReady
+	4,  // 3: messages.SessionRequest.to_in_lobby:type_name -> messages.RequestToInLobby
+	5,  // 4: messages.SessionRequest.loaded:type_name -> messages.RequestLoaded
+	6,  // 5: messages.SessionRequest.in_game_frames:type_name -> messages.RequestInGameFrames
+	10, // 6: messages.SessionRequest.other:type_name -> messages.RequestOther
+	8,  // 7: messages.SessionRequest.end_game:type_name -> messages.RequestEndGame
+	9,  // 8: messages.SessionRequest.post_game_data:type_name -> messages.RequestPostGameData
+	11, // 9: messages.SessionRequest.transfer_owner:type_name -> messages.RequestTransferOwner
+	12, // 10: messages.SessionRequest.replay_control:type_name -> messages.RequestReplayControl
+	13, // 11: messages.SessionRequest.subscribe_chunks:type_name -> messages.RequestSubscribeChunks
+	14, // 12: messages.SessionRequest.unsubscribe_chunks:type_name -> messages.RequestUnsubscribeChunks
+	15, // 13: messages.SessionRequest.pause:type_name -> messages.RequestPause
+	16, // 14: messages.SessionRequest.resume:type_name -> messages.RequestResume
+	7,  // 15: messages.RequestInGameFrames.inputs:type_name -> messages.InputEntry
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_session_req_proto_init() }
@@ -779,18 +1302,26 @@ func file_session_req_proto_init() {
 		(*SessionRequest_Other)(nil),
 		(*SessionRequest_EndGame)(nil),
 		(*SessionRequest_PostGameData)(nil),
+		(*SessionRequest_TransferOwner)(nil),
+		(*SessionRequest_ReplayControl)(nil),
+		(*SessionRequest_SubscribeChunks)(nil),
+		(*SessionRequest_UnsubscribeChunks)(nil),
+		(*SessionRequest_Pause)(nil),
+		(*SessionRequest_Resume)(nil),
 	}
 	file_session_req_proto_msgTypes[3].OneofWrappers = []any{}
+	file_session_req_proto_msgTypes[5].OneofWrappers = []any{}
 	file_session_req_proto_msgTypes[6].OneofWrappers = []any{}
-	file_session_req_proto_msgTypes[7].OneofWrappers = []any{}
 	file_session_req_proto_msgTypes[8].OneofWrappers = []any{}
+	file_session_req_proto_msgTypes[9].OneofWrappers = []any{}
+	file_session_req_proto_msgTypes[12].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_session_req_proto_rawDesc), len(file_session_req_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -23,6 +23,203 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 连接或房间被关闭的原因
+// 客户端可据此向用户展示断开原因
+type CloseReason int32
+
+const (
+	CloseReason_CLOSE_REASON_UNSPECIFIED CloseReason = 0
+	// 房间空闲或无人，被服务器回收
+	CloseReason_CLOSE_REASON_IDLE CloseReason = 1
+	// 房主关闭了房间
+	CloseReason_CLOSE_REASON_HOST_CLOSED CloseReason = 2
+	// 游戏已结束
+	CloseReason_CLOSE_REASON_GAME_ENDED CloseReason = 3
+	// 服务器正在关闭
+	CloseReason_CLOSE_REASON_SERVER_SHUTDOWN CloseReason = 4
+	// 被踢出房间
+	CloseReason_CLOSE_REASON_KICKED CloseReason = 5
+	// 同一玩家在别处登录，旧连接被顶替
+	CloseReason_CLOSE_REASON_DUPLICATE_LOGIN CloseReason = 6
+	// 客户端接收过慢，出站队列长时间饱和
+	CloseReason_CLOSE_REASON_SLOW_CONNECTION CloseReason = 7
+	// 客户端多次发送非法请求
+	CloseReason_CLOSE_REASON_PROTOCOL_VIOLATION CloseReason = 8
+)
+
+// Enum value maps for CloseReason.
+var (
+	CloseReason_name = map[int32]string{
+		0: "CLOSE_REASON_UNSPECIFIED",
+		1: "CLOSE_REASON_IDLE",
+		2: "CLOSE_REASON_HOST_CLOSED",
+		3: "CLOSE_REASON_GAME_ENDED",
+		4: "CLOSE_REASON_SERVER_SHUTDOWN",
+		5: "CLOSE_REASON_KICKED",
+		6: "CLOSE_REASON_DUPLICATE_LOGIN",
+		7: "CLOSE_REASON_SLOW_CONNECTION",
+		8: "CLOSE_REASON_PROTOCOL_VIOLATION",
+	}
+	CloseReason_value = map[string]int32{
+		"CLOSE_REASON_UNSPECIFIED":        0,
+		"CLOSE_REASON_IDLE":               1,
+		"CLOSE_REASON_HOST_CLOSED":        2,
+		"CLOSE_REASON_GAME_ENDED":         3,
+		"CLOSE_REASON_SERVER_SHUTDOWN":    4,
+		"CLOSE_REASON_KICKED":             5,
+		"CLOSE_REASON_DUPLICATE_LOGIN":    6,
+		"CLOSE_REASON_SLOW_CONNECTION":    7,
+		"CLOSE_REASON_PROTOCOL_VIOLATION": 8,
+	}
+)
+
+func (x CloseReason) Enum() *CloseReason {
+	p := new(CloseReason)
+	*p = x
+	return p
+}
+
+func (x CloseReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CloseReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_session_resp_proto_enumTypes[0].Descriptor()
+}
+
+func (CloseReason) Type() protoreflect.EnumType {
+	return &file_session_resp_proto_enumTypes[0]
+}
+
+func (x CloseReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CloseReason.Descriptor instead.
+func (CloseReason) EnumDescriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{0}
+}
+
+// 请求被拒绝的原因
+type ErrorCode int32
+
+const (
+	ErrorCode_ERROR_CODE_UNSPECIFIED ErrorCode = 0
+	// 请求在房间当前阶段不合法
+	ErrorCode_ERROR_CODE_ILLEGAL_STAGE ErrorCode = 1
+	// 请求的阶段切换不合法
+	ErrorCode_ERROR_CODE_ILLEGAL_TRANSITION ErrorCode = 2
+	// 没有权限发起该请求，例如非房主请求切换阶段
+	ErrorCode_ERROR_CODE_PERMISSION_DENIED ErrorCode = 3
+	// 请求内容不合法，例如输入过大或帧号越界
+	ErrorCode_ERROR_CODE_INVALID_REQUEST ErrorCode = 4
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0: "ERROR_CODE_UNSPECIFIED",
+		1: "ERROR_CODE_ILLEGAL_STAGE",
+		2: "ERROR_CODE_ILLEGAL_TRANSITION",
+		3: "ERROR_CODE_PERMISSION_DENIED",
+		4: "ERROR_CODE_INVALID_REQUEST",
+	}
+	ErrorCode_value = map[string]int32{
+		"ERROR_CODE_UNSPECIFIED":        0,
+		"ERROR_CODE_ILLEGAL_STAGE":      1,
+		"ERROR_CODE_ILLEGAL_TRANSITION": 2,
+		"ERROR_CODE_PERMISSION_DENIED":  3,
+		"ERROR_CODE_INVALID_REQUEST":    4,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_session_resp_proto_enumTypes[1].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_session_resp_proto_enumTypes[1]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{1}
+}
+
+// 暂停或恢复的原因
+type PauseReason int32
+
+const (
+	PauseReason_PAUSE_REASON_UNSPECIFIED PauseReason = 0
+	// 玩家请求或投票
+	PauseReason_PAUSE_REASON_PLAYER_REQUEST PauseReason = 1
+	// 玩家在对局中断线，自动暂停
+	PauseReason_PAUSE_REASON_PLAYER_DISCONNECTED PauseReason = 2
+	// 断线的玩家均已重连或离开，自动恢复
+	PauseReason_PAUSE_REASON_DISCONNECT_RESOLVED PauseReason = 3
+	// 发起暂停的玩家的暂停时长已用尽，自动恢复
+	PauseReason_PAUSE_REASON_BUDGET_EXHAUSTED PauseReason = 4
+)
+
+// Enum value maps for PauseReason.
+var (
+	PauseReason_name = map[int32]string{
+		0: "PAUSE_REASON_UNSPECIFIED",
+		1: "PAUSE_REASON_PLAYER_REQUEST",
+		2: "PAUSE_REASON_PLAYER_DISCONNECTED",
+		3: "PAUSE_REASON_DISCONNECT_RESOLVED",
+		4: "PAUSE_REASON_BUDGET_EXHAUSTED",
+	}
+	PauseReason_value = map[string]int32{
+		"PAUSE_REASON_UNSPECIFIED":         0,
+		"PAUSE_REASON_PLAYER_REQUEST":      1,
+		"PAUSE_REASON_PLAYER_DISCONNECTED": 2,
+		"PAUSE_REASON_DISCONNECT_RESOLVED": 3,
+		"PAUSE_REASON_BUDGET_EXHAUSTED":    4,
+	}
+)
+
+func (x PauseReason) Enum() *PauseReason {
+	p := new(PauseReason)
+	*p = x
+	return p
+}
+
+func (x PauseReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PauseReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_session_resp_proto_enumTypes[2].Descriptor()
+}
+
+func (PauseReason) Type() protoreflect.EnumType {
+	return &file_session_resp_proto_enumTypes[2]
+}
+
+func (x PauseReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PauseReason.Descriptor instead.
+func (PauseReason) EnumDescriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{2}
+}
+
 type SessionResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Payload:
@@ -36,6 +233,15 @@ type SessionResponse struct {
 	//	*SessionResponse_InGameFrames
 	//	*SessionResponse_EndGame
 	//	*SessionResponse_Other
+	//	*SessionResponse_Snapshot
+	//	*SessionResponse_Kicked
+	//	*SessionResponse_ReplayState
+	//	*SessionResponse_Desync
+	//	*SessionResponse_ChunkSubscriptions
+	//	*SessionResponse_Error
+	//	*SessionResponse_ReadyCountdown
+	//	*SessionResponse_Paused
+	//	*SessionResponse_ClosePending
 	Payload       isSessionResponse_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -159,6 +365,87 @@ func (x *SessionResponse) GetOther() *ResponseOther {
 	return nil
 }
 
+func (x *SessionResponse) GetSnapshot() *ResponseSnapshot {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_Snapshot); ok {
+			return x.Snapshot
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetKicked() *ResponseKicked {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_Kicked); ok {
+			return x.Kicked
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetReplayState() *ResponseReplayState {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_ReplayState); ok {
+			return x.ReplayState
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetDesync() *ResponseDesync {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_Desync); ok {
+			return x.Desync
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetChunkSubscriptions() *ResponseChunkSubscriptions {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_ChunkSubscriptions); ok {
+			return x.ChunkSubscriptions
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetError() *ResponseError {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetReadyCountdown() *ResponseReadyCountdown {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_ReadyCountdown); ok {
+			return x.ReadyCountdown
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetPaused() *ResponsePaused {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_Paused); ok {
+			return x.Paused
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetClosePending() *ResponseClosePending {
+	if x != nil {
+		if x, ok := x.Payload.(*SessionResponse_ClosePending); ok {
+			return x.ClosePending
+		}
+	}
+	return nil
+}
+
 type isSessionResponse_Payload interface {
 	isSessionResponse_Payload()
 }
@@ -199,6 +486,42 @@ type SessionResponse_Other struct {
 	Other *ResponseOther `protobuf:"bytes,9,opt,name=other,proto3,oneof"`
 }
 
+type SessionResponse_Snapshot struct {
+	Snapshot *ResponseSnapshot `protobuf:"bytes,10,opt,name=snapshot,proto3,oneof"`
+}
+
+type SessionResponse_Kicked struct {
+	Kicked *ResponseKicked `protobuf:"bytes,11,opt,name=kicked,proto3,oneof"`
+}
+
+type SessionResponse_ReplayState struct {
+	ReplayState *ResponseReplayState `protobuf:"bytes,12,opt,name=replay_state,json=replayState,proto3,oneof"`
+}
+
+type SessionResponse_Desync struct {
+	Desync *ResponseDesync `protobuf:"bytes,13,opt,name=desync,proto3,oneof"`
+}
+
+type SessionResponse_ChunkSubscriptions struct {
+	ChunkSubscriptions *ResponseChunkSubscriptions `protobuf:"bytes,14,opt,name=chunk_subscriptions,json=chunkSubscriptions,proto3,oneof"`
+}
+
+type SessionResponse_Error struct {
+	Error *ResponseError `protobuf:"bytes,15,opt,name=error,proto3,oneof"`
+}
+
+type SessionResponse_ReadyCountdown struct {
+	ReadyCountdown *ResponseReadyCountdown `protobuf:"bytes,16,opt,name=ready_countdown,json=readyCountdown,proto3,oneof"`
+}
+
+type SessionResponse_Paused struct {
+	Paused *ResponsePaused `protobuf:"bytes,17,opt,name=paused,proto3,oneof"`
+}
+
+type SessionResponse_ClosePending struct {
+	ClosePending *ResponseClosePending `protobuf:"bytes,18,opt,name=close_pending,json=closePending,proto3,oneof"`
+}
+
 func (*SessionResponse_Join) isSessionResponse_Payload() {}
 
 func (*SessionResponse_RoomInfoChanged) isSessionResponse_Payload() {}
@@ -217,6 +540,24 @@ func (*SessionResponse_EndGame) isSessionResponse_Payload() {}
 
 func (*SessionResponse_Other) isSessionResponse_Payload() {}
 
+func (*SessionResponse_Snapshot) isSessionResponse_Payload() {}
+
+func (*SessionResponse_Kicked) isSessionResponse_Payload() {}
+
+func (*SessionResponse_ReplayState) isSessionResponse_Payload() {}
+
+func (*SessionResponse_Desync) isSessionResponse_Payload() {}
+
+func (*SessionResponse_ChunkSubscriptions) isSessionResponse_Payload() {}
+
+func (*SessionResponse_Error) isSessionResponse_Payload() {}
+
+func (*SessionResponse_ReadyCountdown) isSessionResponse_Payload() {}
+
+func (*SessionResponse_Paused) isSessionResponse_Payload() {}
+
+func (*SessionResponse_ClosePending) isSessionResponse_Payload() {}
+
 type RoomInfo struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	RoomKey        string                 `protobuf:"bytes,2,opt,name=RoomKey,proto3" json:"RoomKey,omitempty"`
@@ -224,7 +565,9 @@ type RoomInfo struct {
 	CurrentPlayers int32                  `protobuf:"varint,5,opt,name=CurrentPlayers,proto3" json:"CurrentPlayers,omitempty"`
 	PlayerIDs      []uint32               `protobuf:"varint,6,rep,packed,name=PlayerIDs,proto3" json:"PlayerIDs,omitempty"`
 	// 附加数据
-	Data          []byte `protobuf:"bytes,7,opt,name=data,proto3,oneof" json:"data,omitempty"`
+	Data []byte `protobuf:"bytes,7,opt,name=data,proto3,oneof" json:"data,omitempty"`
+	// 房主的玩家ID，房间内没有玩家时为空
+	OwnerID       *uint32 `protobuf:"varint,8,opt,name=OwnerID,proto3,oneof" json:"OwnerID,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -294,6 +637,13 @@ func (x *RoomInfo) GetData() []byte {
 	return nil
 }
 
+func (x *RoomInfo) GetOwnerID() uint32 {
+	if x != nil && x.OwnerID != nil {
+		return *x.OwnerID
+	}
+	return 0
+}
+
 type ResponseJoinSuccess struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	RoomID         uint32                 `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
@@ -543,9 +893,12 @@ func (x *ResponseRoomInfoChanged) GetRoomInfo() *RoomInfo {
 	return nil
 }
 
+// 房间关闭前发送给所有剩余客户端的最后一条消息
 type ResponseRoomClosed struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Reason        string                 `protobuf:"bytes,1,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 供展示或调试的说明文本
+	Reason        string      `protobuf:"bytes,1,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	Code          CloseReason `protobuf:"varint,2,opt,name=code,proto3,enum=messages.CloseReason" json:"code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -587,40 +940,40 @@ func (x *ResponseRoomClosed) GetReason() string {
 	return ""
 }
 
-// 通知客户端房间阶段变更
-// 响应体使用不同的NewStage字段而非不同的响应类型，以方便客户端分支处理
-type ResponseStageChange struct {
+func (x *ResponseRoomClosed) GetCode() CloseReason {
+	if x != nil {
+		return x.Code
+	}
+	return CloseReason_CLOSE_REASON_UNSPECIFIED
+}
+
+// 房间即将被关闭的预告，例如服务器关闭时等待进行中的对局结束
+// 对局在 remaining_ms 内结束则正常进入 PostGame，否则房间被强制关闭
+type ResponseClosePending struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// 新字段的常量
-	// STAGE_InLobby   Stage = 0x20 // InLobby (房间.等待中): 房间刚被创建,所有人还在房间中，等待玩家加入，房主可以设置游戏。
-	// STAGE_Preparing Stage = 0x21 // Preparing (房间.准备中): 房主已发起游戏，所有玩家选择装备并确认准备。
-	// STAGE_Loading   Stage = 0x22 // Loading (游戏.加载中): 游戏开始前的加载阶段，所有玩家准备完毕后进入 InGame。
-	// STAGE_InGame    Stage = 0x23 // InGame (游戏.游戏中): 所有玩家准备就绪，游戏正式开始，由定时器驱动逻辑。
-	// STAGE_PostGame  Stage = 0x24 // PostGame (游戏.游戏后结算): 游戏结束，显示战绩，等待返回大厅。
-	// STAGE_CLOSED Stage = 0xEE
-	// STAGE_Error  Stage = 0xFF
-	NewStage uint32 `protobuf:"varint,1,opt,name=NewStage,proto3" json:"NewStage,omitempty"`
-	// 可以携带更多的数据
-	// 本框架自身不用此字段
-	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof" json:"data,omitempty"`
+	Code  CloseReason            `protobuf:"varint,1,opt,name=code,proto3,enum=messages.CloseReason" json:"code,omitempty"`
+	// 供展示或调试的说明文本
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// 距离强制关闭的时间 (ms)，0 为立即关闭
+	RemainingMs   uint32 `protobuf:"varint,3,opt,name=remaining_ms,json=remainingMs,proto3" json:"remaining_ms,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResponseStageChange) Reset() {
-	*x = ResponseStageChange{}
+func (x *ResponseClosePending) Reset() {
+	*x = ResponseClosePending{}
 	mi := &file_session_resp_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResponseStageChange) String() string {
+func (x *ResponseClosePending) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseStageChange) ProtoMessage() {}
+func (*ResponseClosePending) ProtoMessage() {}
 
-func (x *ResponseStageChange) ProtoReflect() protoreflect.Message {
+func (x *ResponseClosePending) ProtoReflect() protoreflect.Message {
 	mi := &file_session_resp_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -632,48 +985,56 @@ func (x *ResponseStageChange) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseStageChange.ProtoReflect.Descriptor instead.
-func (*ResponseStageChange) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResponseClosePending.ProtoReflect.Descriptor instead.
+func (*ResponseClosePending) Descriptor() ([]byte, []int) {
 	return file_session_resp_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ResponseStageChange) GetNewStage() uint32 {
+func (x *ResponseClosePending) GetCode() CloseReason {
 	if x != nil {
-		return x.NewStage
+		return x.Code
 	}
-	return 0
+	return CloseReason_CLOSE_REASON_UNSPECIFIED
 }
 
-func (x *ResponseStageChange) GetData() []byte {
+func (x *ResponseClosePending) GetReason() string {
 	if x != nil {
-		return x.Data
+		return x.Reason
 	}
-	return nil
+	return ""
 }
 
-// 更新已经ready的人数
-type ResponseReadyCountUpdate struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ReadyPlayerIds []uint32               `protobuf:"varint,1,rep,packed,name=ready_player_ids,json=readyPlayerIds,proto3" json:"ready_player_ids,omitempty"`
-	TotalCount     uint32                 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *ResponseClosePending) GetRemainingMs() uint32 {
+	if x != nil {
+		return x.RemainingMs
+	}
+	return 0
 }
 
-func (x *ResponseReadyCountUpdate) Reset() {
-	*x = ResponseReadyCountUpdate{}
+// 请求被拒绝时发送给请求者，连接保持不变
+type ResponseError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  ErrorCode              `protobuf:"varint,1,opt,name=code,proto3,enum=messages.ErrorCode" json:"code,omitempty"`
+	// 供展示或调试的说明文本
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseError) Reset() {
+	*x = ResponseError{}
 	mi := &file_session_resp_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResponseReadyCountUpdate) String() string {
+func (x *ResponseError) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseReadyCountUpdate) ProtoMessage() {}
+func (*ResponseError) ProtoMessage() {}
 
-func (x *ResponseReadyCountUpdate) ProtoReflect() protoreflect.Message {
+func (x *ResponseError) ProtoReflect() protoreflect.Message {
 	mi := &file_session_resp_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -685,48 +1046,48 @@ func (x *ResponseReadyCountUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseReadyCountUpdate.ProtoReflect.Descriptor instead.
-func (*ResponseReadyCountUpdate) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResponseError.ProtoReflect.Descriptor instead.
+func (*ResponseError) Descriptor() ([]byte, []int) {
 	return file_session_resp_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ResponseReadyCountUpdate) GetReadyPlayerIds() []uint32 {
+func (x *ResponseError) GetCode() ErrorCode {
 	if x != nil {
-		return x.ReadyPlayerIds
+		return x.Code
 	}
-	return nil
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
 }
 
-func (x *ResponseReadyCountUpdate) GetTotalCount() uint32 {
+func (x *ResponseError) GetMessage() string {
 	if x != nil {
-		return x.TotalCount
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-// 更新已经loaded的人数
-type ResponseLoadedCountUpdate struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	LoadedPlayerIds []uint32               `protobuf:"varint,1,rep,packed,name=loaded_player_ids,json=loadedPlayerIds,proto3" json:"loaded_player_ids,omitempty"`
-	TotalCount      uint32                 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// 单个玩家被移出房间前发送给该玩家的最后一条消息
+type ResponseKicked struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          CloseReason            `protobuf:"varint,1,opt,name=code,proto3,enum=messages.CloseReason" json:"code,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResponseLoadedCountUpdate) Reset() {
-	*x = ResponseLoadedCountUpdate{}
+func (x *ResponseKicked) Reset() {
+	*x = ResponseKicked{}
 	mi := &file_session_resp_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResponseLoadedCountUpdate) String() string {
+func (x *ResponseKicked) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseLoadedCountUpdate) ProtoMessage() {}
+func (*ResponseKicked) ProtoMessage() {}
 
-func (x *ResponseLoadedCountUpdate) ProtoReflect() protoreflect.Message {
+func (x *ResponseKicked) ProtoReflect() protoreflect.Message {
 	mi := &file_session_resp_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -738,59 +1099,556 @@ func (x *ResponseLoadedCountUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseLoadedCountUpdate.ProtoReflect.Descriptor instead.
-func (*ResponseLoadedCountUpdate) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResponseKicked.ProtoReflect.Descriptor instead.
+func (*ResponseKicked) Descriptor() ([]byte, []int) {
 	return file_session_resp_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ResponseLoadedCountUpdate) GetLoadedPlayerIds() []uint32 {
+func (x *ResponseKicked) GetCode() CloseReason {
 	if x != nil {
-		return x.LoadedPlayerIds
+		return x.Code
 	}
-	return nil
+	return CloseReason_CLOSE_REASON_UNSPECIFIED
 }
 
-func (x *ResponseLoadedCountUpdate) GetTotalCount() uint32 {
+func (x *ResponseKicked) GetReason() string {
 	if x != nil {
-		return x.TotalCount
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-// 用户的输入序列
-// 1. 影响权威游戏世界
-// 2. 直接进行帧同步广播
-type ClientInputData struct {
+// 通知客户端房间阶段变更
+// 响应体使用不同的NewStage字段而非不同的响应类型，以方便客户端分支处理
+type ResponseStageChange struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	Uid   uint32                 `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`
-	// 用户发送数据时,其所在的FrameID
-	// 游戏世界需要自行处理例如延迟补偿等机制
-	//
-	// e.g. Lag Compensation, Display of Targets
-	FrameId uint32 `protobuf:"varint,2,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
-	// 解析后的框架无关的额外Bytes,不带有帧ID的原始输入队列
-	// 当然也可以是一个键盘扫描码byte
-	// e.g. [{"method" : "move", "direction": "up"}, {"method": "attack", "targetID": 1234}]
-	Data          []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// 新字段的常量
+	//STAGE_InLobby   Stage = 0x20 // InLobby (房间.等待中): 房间刚被创建,所有人还在房间中，等待玩家加入，房主可以设置游戏。
+	//STAGE_Preparing Stage = 0x21 // Preparing (房间.准备中): 房主已发起游戏，所有玩家选择装备并确认准备。
+	//STAGE_Loading   Stage = 0x22 // Loading (游戏.加载中): 游戏开始前的加载阶段，所有玩家准备完毕后进入 InGame。
+	//STAGE_InGame    Stage = 0x23 // InGame (游戏.游戏中): 所有玩家准备就绪，游戏正式开始，由定时器驱动逻辑。
+	//STAGE_PostGame  Stage = 0x24 // PostGame (游戏.游戏后结算): 游戏结束，显示战绩，等待返回大厅。
+	//STAGE_CLOSED Stage = 0xEE
+	//STAGE_Error  Stage = 0xFF
+	NewStage uint32 `protobuf:"varint,1,opt,name=NewStage,proto3" json:"NewStage,omitempty"`
+	// 可以携带更多的数据
+	// 本框架自身不用此字段
+	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseStageChange) Reset() {
+	*x = ResponseStageChange{}
+	mi := &file_session_resp_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseStageChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseStageChange) ProtoMessage() {}
+
+func (x *ResponseStageChange) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseStageChange.ProtoReflect.Descriptor instead.
+func (*ResponseStageChange) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ResponseStageChange) GetNewStage() uint32 {
+	if x != nil {
+		return x.NewStage
+	}
+	return 0
+}
+
+func (x *ResponseStageChange) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// 更新已经ready的人数
+type ResponseReadyCountUpdate struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ReadyPlayerIds []uint32               `protobuf:"varint,1,rep,packed,name=ready_player_ids,json=readyPlayerIds,proto3" json:"ready_player_ids,omitempty"`
+	TotalCount     uint32                 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResponseReadyCountUpdate) Reset() {
+	*x = ResponseReadyCountUpdate{}
+	mi := &file_session_resp_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseReadyCountUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseReadyCountUpdate) ProtoMessage() {}
+
+func (x *ResponseReadyCountUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseReadyCountUpdate.ProtoReflect.Descriptor instead.
+func (*ResponseReadyCountUpdate) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ResponseReadyCountUpdate) GetReadyPlayerIds() []uint32 {
+	if x != nil {
+		return x.ReadyPlayerIds
+	}
+	return nil
+}
+
+func (x *ResponseReadyCountUpdate) GetTotalCount() uint32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+// 准备倒计时开始或取消
+// 足够多的玩家准备后开始倒计时，结束时仍未准备的玩家由游戏世界决定自动准备或被踢出
+type ResponseReadyCountdown struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// true 为倒计时开始，false 为倒计时取消
+	Active bool `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	// 倒计时剩余的毫秒数，取消时为 0
+	RemainingMs   uint32 `protobuf:"varint,2,opt,name=remaining_ms,json=remainingMs,proto3" json:"remaining_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseReadyCountdown) Reset() {
+	*x = ResponseReadyCountdown{}
+	mi := &file_session_resp_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseReadyCountdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseReadyCountdown) ProtoMessage() {}
+
+func (x *ResponseReadyCountdown) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseReadyCountdown.ProtoReflect.Descriptor instead.
+func (*ResponseReadyCountdown) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ResponseReadyCountdown) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *ResponseReadyCountdown) GetRemainingMs() uint32 {
+	if x != nil {
+		return x.RemainingMs
+	}
+	return 0
+}
+
+// 更新已经loaded的人数
+type ResponseLoadedCountUpdate struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	LoadedPlayerIds []uint32               `protobuf:"varint,1,rep,packed,name=loaded_player_ids,json=loadedPlayerIds,proto3" json:"loaded_player_ids,omitempty"`
+	TotalCount      uint32                 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// 每位玩家的加载进度
+	Progress      []*LoadProgress `protobuf:"bytes,3,rep,name=progress,proto3" json:"progress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseLoadedCountUpdate) Reset() {
+	*x = ResponseLoadedCountUpdate{}
+	mi := &file_session_resp_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseLoadedCountUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseLoadedCountUpdate) ProtoMessage() {}
+
+func (x *ResponseLoadedCountUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseLoadedCountUpdate.ProtoReflect.Descriptor instead.
+func (*ResponseLoadedCountUpdate) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ResponseLoadedCountUpdate) GetLoadedPlayerIds() []uint32 {
+	if x != nil {
+		return x.LoadedPlayerIds
+	}
+	return nil
+}
+
+func (x *ResponseLoadedCountUpdate) GetTotalCount() uint32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ResponseLoadedCountUpdate) GetProgress() []*LoadProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+// 玩家的加载进度
+type LoadProgress struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId uint32                 `protobuf:"varint,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	// 百分比，0-100，加载完毕为 100
+	Percent       uint32 `protobuf:"varint,2,opt,name=percent,proto3" json:"percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadProgress) Reset() {
+	*x = LoadProgress{}
+	mi := &file_session_resp_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadProgress) ProtoMessage() {}
+
+func (x *LoadProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadProgress.ProtoReflect.Descriptor instead.
+func (*LoadProgress) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LoadProgress) GetPlayerId() uint32 {
+	if x != nil {
+		return x.PlayerId
+	}
+	return 0
+}
+
+func (x *LoadProgress) GetPercent() uint32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+// 用户的输入序列
+// 1. 影响权威游戏世界
+// 2. 直接进行帧同步广播
+type ClientInputData struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Uid   uint32                 `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	// 用户发送数据时,其所在的FrameID
+	// 游戏世界需要自行处理例如延迟补偿等机制
+	//
+	// e.g. Lag Compensation, Display of Targets
+	FrameId uint32 `protobuf:"varint,2,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
+	// 解析后的框架无关的额外Bytes,不带有帧ID的原始输入队列
+	// 当然也可以是一个键盘扫描码byte
+	// e.g. [{"method" : "move", "direction": "up"}, {"method": "attack", "targetID": 1234}]
+	Data          []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientInputData) Reset() {
+	*x = ClientInputData{}
+	mi := &file_session_resp_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientInputData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientInputData) ProtoMessage() {}
+
+func (x *ClientInputData) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientInputData.ProtoReflect.Descriptor instead.
+func (*ClientInputData) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ClientInputData) GetUid() uint32 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *ClientInputData) GetFrameId() uint32 {
+	if x != nil {
+		return x.FrameId
+	}
+	return 0
+}
+
+func (x *ClientInputData) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// 游戏事件
+// 帧同步游戏事件中，客户端也能通过InputData计算出和权威游戏世界相同的权威结果
+// 因此事件数据主要用于无法通过 InputData 计算出的结果，例如无关游戏逻辑的私聊等
+type WorldEventData struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 事件对哪个帧号造成影响
+	// 即事件后的下一帧
+	FrameId uint32 `protobuf:"varint,1,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
+	// 事件的原始数据队列
+	//
+	// 如果是超大地图需要分chunk的游戏，此data也推荐用于顺便携带某next帧的某chunk状态同步数据
+	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorldEventData) Reset() {
+	*x = WorldEventData{}
+	mi := &file_session_resp_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorldEventData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorldEventData) ProtoMessage() {}
+
+func (x *WorldEventData) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorldEventData.ProtoReflect.Descriptor instead.
+func (*WorldEventData) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WorldEventData) GetFrameId() uint32 {
+	if x != nil {
+		return x.FrameId
+	}
+	return 0
+}
+
+func (x *WorldEventData) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// 每一帧的完整数据包
+type FrameData struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 本次服务端将要步进到的帧 ID
+	// FrameData和input, events都带有FrameID字段是为了延迟补偿
+	// 告知客户端，之前的帧中有遗漏的操作和时间发生
+	FrameId uint32 `protobuf:"varint,1,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
+	// 服务端确认客户端的最早要步进到的ack帧ID
+	// 以方便客户端缓存快照以时间回溯
+	OldestAckFrameId uint32 `protobuf:"varint,5,opt,name=oldestAckFrameId,proto3" json:"oldestAckFrameId,omitempty"`
+	// 帧数据内容
+	// ---
+	// 包含了本帧服务器接受到的现在或迟到所有用户的输入
+	InputArray []*ClientInputData `protobuf:"bytes,2,rep,name=input_array,json=inputArray,proto3" json:"input_array,omitempty"`
+	// 包含了本帧或迟到的权威服务器裁决的发生的所有游戏世界事件
+	Events []*WorldEventData `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	// 本帧数据所属的 chunk，未分 chunk 的游戏均为 0
+	// 同一帧号下每个订阅的 chunk 各有一个 FrameData
+	ChunkId       uint32 `protobuf:"varint,6,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FrameData) Reset() {
+	*x = FrameData{}
+	mi := &file_session_resp_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FrameData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FrameData) ProtoMessage() {}
+
+func (x *FrameData) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FrameData.ProtoReflect.Descriptor instead.
+func (*FrameData) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *ClientInputData) Reset() {
-	*x = ClientInputData{}
-	mi := &file_session_resp_proto_msgTypes[10]
+func (x *FrameData) GetFrameId() uint32 {
+	if x != nil {
+		return x.FrameId
+	}
+	return 0
+}
+
+func (x *FrameData) GetOldestAckFrameId() uint32 {
+	if x != nil {
+		return x.OldestAckFrameId
+	}
+	return 0
+}
+
+func (x *FrameData) GetInputArray() []*ClientInputData {
+	if x != nil {
+		return x.InputArray
+	}
+	return nil
+}
+
+func (x *FrameData) GetEvents() []*WorldEventData {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *FrameData) GetChunkId() uint32 {
+	if x != nil {
+		return x.ChunkId
+	}
+	return 0
+}
+
+type ResponseInGameFrames struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Frames []*FrameData           `protobuf:"bytes,1,rep,name=frames,proto3" json:"frames,omitempty"`
+	// 服务端已收到的该玩家输入的最新帧号，客户端可据此裁剪冗余发送的输入
+	LastInputFrameId uint32 `protobuf:"varint,2,opt,name=last_input_frame_id,json=lastInputFrameId,proto3" json:"last_input_frame_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ResponseInGameFrames) Reset() {
+	*x = ResponseInGameFrames{}
+	mi := &file_session_resp_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ClientInputData) String() string {
+func (x *ResponseInGameFrames) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClientInputData) ProtoMessage() {}
+func (*ResponseInGameFrames) ProtoMessage() {}
 
-func (x *ClientInputData) ProtoReflect() protoreflect.Message {
-	mi := &file_session_resp_proto_msgTypes[10]
+func (x *ResponseInGameFrames) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -801,63 +1659,117 @@ func (x *ClientInputData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClientInputData.ProtoReflect.Descriptor instead.
-func (*ClientInputData) Descriptor() ([]byte, []int) {
-	return file_session_resp_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ResponseInGameFrames.ProtoReflect.Descriptor instead.
+func (*ResponseInGameFrames) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *ClientInputData) GetUid() uint32 {
+func (x *ResponseInGameFrames) GetFrames() []*FrameData {
 	if x != nil {
-		return x.Uid
+		return x.Frames
+	}
+	return nil
+}
+
+func (x *ResponseInGameFrames) GetLastInputFrameId() uint32 {
+	if x != nil {
+		return x.LastInputFrameId
 	}
 	return 0
 }
 
-func (x *ClientInputData) GetFrameId() uint32 {
+// 状态快照，用于重连、迟到加入或严重落后的客户端追帧
+// 客户端加载快照后，依次步进 frames 中的帧即可追上服务端
+type ResponseSnapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 快照对应的帧号，即快照为步进到达此帧后的游戏世界状态
+	FrameId uint32 `protobuf:"varint,1,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
+	// 游戏世界 GetSnapshot 提供的快照数据
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// 快照之后到当前的帧数据
+	Frames        []*FrameData `protobuf:"bytes,3,rep,name=frames,proto3" json:"frames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseSnapshot) Reset() {
+	*x = ResponseSnapshot{}
+	mi := &file_session_resp_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseSnapshot) ProtoMessage() {}
+
+func (x *ResponseSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseSnapshot.ProtoReflect.Descriptor instead.
+func (*ResponseSnapshot) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResponseSnapshot) GetFrameId() uint32 {
 	if x != nil {
 		return x.FrameId
 	}
 	return 0
 }
 
-func (x *ClientInputData) GetData() []byte {
+func (x *ResponseSnapshot) GetData() []byte {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-// 游戏事件
-// 帧同步游戏事件中，客户端也能通过InputData计算出和权威游戏世界相同的权威结果
-// 因此事件数据主要用于无法通过 InputData 计算出的结果，例如无关游戏逻辑的私聊等
-type WorldEventData struct {
+func (x *ResponseSnapshot) GetFrames() []*FrameData {
+	if x != nil {
+		return x.Frames
+	}
+	return nil
+}
+
+// 客户端上报的状态校验和与权威校验和不一致
+// 同一玩家在重新同步前只会收到一次
+type ResponseDesync struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// 事件对哪个帧号造成影响
-	// 即事件后的下一帧
+	// 最早检测到不一致的帧号
 	FrameId uint32 `protobuf:"varint,1,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
-	// 事件的原始数据队列
-	//
-	// 如果是超大地图需要分chunk的游戏，此data也推荐用于顺便携带某next帧的某chunk状态同步数据
-	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// 用于重新同步的权威快照，游戏世界不要求重新同步时为空
+	Resync        *ResponseSnapshot `protobuf:"bytes,2,opt,name=resync,proto3,oneof" json:"resync,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WorldEventData) Reset() {
-	*x = WorldEventData{}
-	mi := &file_session_resp_proto_msgTypes[11]
+func (x *ResponseDesync) Reset() {
+	*x = ResponseDesync{}
+	mi := &file_session_resp_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WorldEventData) String() string {
+func (x *ResponseDesync) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorldEventData) ProtoMessage() {}
+func (*ResponseDesync) ProtoMessage() {}
 
-func (x *WorldEventData) ProtoReflect() protoreflect.Message {
-	mi := &file_session_resp_proto_msgTypes[11]
+func (x *ResponseDesync) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -868,58 +1780,53 @@ func (x *WorldEventData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorldEventData.ProtoReflect.Descriptor instead.
-func (*WorldEventData) Descriptor() ([]byte, []int) {
-	return file_session_resp_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ResponseDesync.ProtoReflect.Descriptor instead.
+func (*ResponseDesync) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *WorldEventData) GetFrameId() uint32 {
+func (x *ResponseDesync) GetFrameId() uint32 {
 	if x != nil {
 		return x.FrameId
 	}
 	return 0
 }
 
-func (x *WorldEventData) GetData() []byte {
+func (x *ResponseDesync) GetResync() *ResponseSnapshot {
 	if x != nil {
-		return x.Data
+		return x.Resync
 	}
 	return nil
 }
 
-// 每一帧的完整数据包
-type FrameData struct {
+// 订阅或取消订阅 chunk 后，告知客户端当前订阅的全部 chunk
+type ResponseChunkSubscriptions struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// 本次服务端将要步进到的帧 ID
-	// FrameData和input, events都带有FrameID字段是为了延迟补偿
-	// 告知客户端，之前的帧中有遗漏的操作和时间发生
-	FrameId uint32 `protobuf:"varint,1,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
-	// 服务端确认客户端的最早要步进到的ack帧ID
-	// 以方便客户端缓存快照以时间回溯
-	OldestAckFrameId uint32 `protobuf:"varint,5,opt,name=oldestAckFrameId,proto3" json:"oldestAckFrameId,omitempty"`
-	// 帧数据内容
-	// ---
-	// 包含了本帧服务器接受到的现在或迟到所有用户的输入
-	InputArray    []*ClientInputData `protobuf:"bytes,2,rep,name=input_array,json=inputArray,proto3" json:"input_array,omitempty"`
+	// 当前订阅的全部 chunk，升序
+	ChunkIds []uint32 `protobuf:"varint,1,rep,packed,name=chunk_ids,json=chunkIds,proto3" json:"chunk_ids,omitempty"`
+	// 本次请求中被拒绝的 chunk
+	RejectedChunkIds []uint32 `protobuf:"varint,2,rep,packed,name=rejected_chunk_ids,json=rejectedChunkIds,proto3" json:"rejected_chunk_ids,omitempty"`
+	// 本次新订阅的 chunk 从此帧号开始接收帧数据
+	SinceFrameId  uint32 `protobuf:"varint,3,opt,name=since_frame_id,json=sinceFrameId,proto3" json:"since_frame_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FrameData) Reset() {
-	*x = FrameData{}
-	mi := &file_session_resp_proto_msgTypes[12]
+func (x *ResponseChunkSubscriptions) Reset() {
+	*x = ResponseChunkSubscriptions{}
+	mi := &file_session_resp_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FrameData) String() string {
+func (x *ResponseChunkSubscriptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FrameData) ProtoMessage() {}
+func (*ResponseChunkSubscriptions) ProtoMessage() {}
 
-func (x *FrameData) ProtoReflect() protoreflect.Message {
-	mi := &file_session_resp_proto_msgTypes[12]
+func (x *ResponseChunkSubscriptions) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -930,54 +1837,65 @@ func (x *FrameData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FrameData.ProtoReflect.Descriptor instead.
-func (*FrameData) Descriptor() ([]byte, []int) {
-	return file_session_resp_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ResponseChunkSubscriptions.ProtoReflect.Descriptor instead.
+func (*ResponseChunkSubscriptions) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *FrameData) GetFrameId() uint32 {
+func (x *ResponseChunkSubscriptions) GetChunkIds() []uint32 {
 	if x != nil {
-		return x.FrameId
+		return x.ChunkIds
 	}
-	return 0
+	return nil
 }
 
-func (x *FrameData) GetOldestAckFrameId() uint32 {
+func (x *ResponseChunkSubscriptions) GetRejectedChunkIds() []uint32 {
 	if x != nil {
-		return x.OldestAckFrameId
+		return x.RejectedChunkIds
 	}
-	return 0
+	return nil
 }
 
-func (x *FrameData) GetInputArray() []*ClientInputData {
+func (x *ResponseChunkSubscriptions) GetSinceFrameId() uint32 {
 	if x != nil {
-		return x.InputArray
+		return x.SinceFrameId
 	}
-	return nil
+	return 0
 }
 
-type ResponseInGameFrames struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Frames        []*FrameData           `protobuf:"bytes,1,rep,name=frames,proto3" json:"frames,omitempty"`
+// 回放房间的播放状态
+// 加入回放房间时以及播放状态改变时发送
+type ResponseReplayState struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Paused bool                   `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	// 播放速度倍率
+	Speed float32 `protobuf:"fixed32,2,opt,name=speed,proto3" json:"speed,omitempty"`
+	// 当前已播放到的帧号
+	FrameId uint32 `protobuf:"varint,3,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
+	// 回放的最后一帧
+	LastFrameId uint32 `protobuf:"varint,4,opt,name=last_frame_id,json=lastFrameId,proto3" json:"last_frame_id,omitempty"`
+	// 本次状态变更为回退到之前的帧，客户端需丢弃本地状态，
+	// 从随后收到的快照或第 1 帧重新开始步进
+	Rewound       bool `protobuf:"varint,5,opt,name=rewound,proto3" json:"rewound,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResponseInGameFrames) Reset() {
-	*x = ResponseInGameFrames{}
-	mi := &file_session_resp_proto_msgTypes[13]
+func (x *ResponseReplayState) Reset() {
+	*x = ResponseReplayState{}
+	mi := &file_session_resp_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResponseInGameFrames) String() string {
+func (x *ResponseReplayState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseInGameFrames) ProtoMessage() {}
+func (*ResponseReplayState) ProtoMessage() {}
 
-func (x *ResponseInGameFrames) ProtoReflect() protoreflect.Message {
-	mi := &file_session_resp_proto_msgTypes[13]
+func (x *ResponseReplayState) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -988,16 +1906,44 @@ func (x *ResponseInGameFrames) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseInGameFrames.ProtoReflect.Descriptor instead.
-func (*ResponseInGameFrames) Descriptor() ([]byte, []int) {
-	return file_session_resp_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ResponseReplayState.ProtoReflect.Descriptor instead.
+func (*ResponseReplayState) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ResponseInGameFrames) GetFrames() []*FrameData {
+func (x *ResponseReplayState) GetPaused() bool {
 	if x != nil {
-		return x.Frames
+		return x.Paused
 	}
-	return nil
+	return false
+}
+
+func (x *ResponseReplayState) GetSpeed() float32 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *ResponseReplayState) GetFrameId() uint32 {
+	if x != nil {
+		return x.FrameId
+	}
+	return 0
+}
+
+func (x *ResponseReplayState) GetLastFrameId() uint32 {
+	if x != nil {
+		return x.LastFrameId
+	}
+	return 0
+}
+
+func (x *ResponseReplayState) GetRewound() bool {
+	if x != nil {
+		return x.Rewound
+	}
+	return false
 }
 
 type ResponseEndGame struct {
@@ -1012,7 +1958,7 @@ type ResponseEndGame struct {
 
 func (x *ResponseEndGame) Reset() {
 	*x = ResponseEndGame{}
-	mi := &file_session_resp_proto_msgTypes[14]
+	mi := &file_session_resp_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1024,7 +1970,7 @@ func (x *ResponseEndGame) String() string {
 func (*ResponseEndGame) ProtoMessage() {}
 
 func (x *ResponseEndGame) ProtoReflect() protoreflect.Message {
-	mi := &file_session_resp_proto_msgTypes[14]
+	mi := &file_session_resp_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1037,7 +1983,7 @@ func (x *ResponseEndGame) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResponseEndGame.ProtoReflect.Descriptor instead.
 func (*ResponseEndGame) Descriptor() ([]byte, []int) {
-	return file_session_resp_proto_rawDescGZIP(), []int{14}
+	return file_session_resp_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *ResponseEndGame) GetStatusCode() uint32 {
@@ -1067,7 +2013,7 @@ type ResponseOther struct {
 
 func (x *ResponseOther) Reset() {
 	*x = ResponseOther{}
-	mi := &file_session_resp_proto_msgTypes[15]
+	mi := &file_session_resp_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1079,7 +2025,7 @@ func (x *ResponseOther) String() string {
 func (*ResponseOther) ProtoMessage() {}
 
 func (x *ResponseOther) ProtoReflect() protoreflect.Message {
-	mi := &file_session_resp_proto_msgTypes[15]
+	mi := &file_session_resp_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1092,7 +2038,7 @@ func (x *ResponseOther) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResponseOther.ProtoReflect.Descriptor instead.
 func (*ResponseOther) Descriptor() ([]byte, []int) {
-	return file_session_resp_proto_rawDescGZIP(), []int{15}
+	return file_session_resp_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *ResponseOther) GetData() []byte {
@@ -1102,11 +2048,110 @@ func (x *ResponseOther) GetData() []byte {
 	return nil
 }
 
+// 对局的暂停状态，在暂停、恢复以及投票变化时广播
+// 暂停期间帧时钟停止，不会产生新的帧
+type ResponsePaused struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Paused bool                   `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	// 冻结的帧号，即暂停时最后一个已生成的帧
+	FrameId uint32 `protobuf:"varint,2,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"`
+	// 发起或导致本次暂停、恢复的玩家，服务器自动恢复时为 0
+	PlayerId uint32      `protobuf:"varint,3,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Reason   PauseReason `protobuf:"varint,4,opt,name=reason,proto3,enum=messages.PauseReason" json:"reason,omitempty"`
+	// 投票规则下，已投票切换暂停状态的玩家与所需票数
+	// 未暂停时为暂停投票，暂停中为恢复投票
+	Votes       []uint32 `protobuf:"varint,5,rep,packed,name=votes,proto3" json:"votes,omitempty"`
+	VotesNeeded uint32   `protobuf:"varint,6,opt,name=votes_needed,json=votesNeeded,proto3" json:"votes_needed,omitempty"`
+	// 本次暂停最多还能持续的时间 (ms)，0 为不限时
+	RemainingMs   uint32 `protobuf:"varint,7,opt,name=remaining_ms,json=remainingMs,proto3" json:"remaining_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponsePaused) Reset() {
+	*x = ResponsePaused{}
+	mi := &file_session_resp_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponsePaused) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponsePaused) ProtoMessage() {}
+
+func (x *ResponsePaused) ProtoReflect() protoreflect.Message {
+	mi := &file_session_resp_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponsePaused.ProtoReflect.Descriptor instead.
+func (*ResponsePaused) Descriptor() ([]byte, []int) {
+	return file_session_resp_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ResponsePaused) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *ResponsePaused) GetFrameId() uint32 {
+	if x != nil {
+		return x.FrameId
+	}
+	return 0
+}
+
+func (x *ResponsePaused) GetPlayerId() uint32 {
+	if x != nil {
+		return x.PlayerId
+	}
+	return 0
+}
+
+func (x *ResponsePaused) GetReason() PauseReason {
+	if x != nil {
+		return x.Reason
+	}
+	return PauseReason_PAUSE_REASON_UNSPECIFIED
+}
+
+func (x *ResponsePaused) GetVotes() []uint32 {
+	if x != nil {
+		return x.Votes
+	}
+	return nil
+}
+
+func (x *ResponsePaused) GetVotesNeeded() uint32 {
+	if x != nil {
+		return x.VotesNeeded
+	}
+	return 0
+}
+
+func (x *ResponsePaused) GetRemainingMs() uint32 {
+	if x != nil {
+		return x.RemainingMs
+	}
+	return 0
+}
+
 var File_session_resp_proto protoreflect.FileDescriptor
 
 const file_session_resp_proto_rawDesc = "" +
 	"\n" +
-	"\x12session_resp.proto\x12\bmessages\"\xfc\x04\n" +
+	"\x12session_resp.proto\x12\bmessages\"\xb4\t\n" +
 	"\x0fSessionResponse\x12,\n" +
 	"\x04join\x18\x01 \x01(\v2\x16.messages.ResponseJoinH\x00R\x04join\x12O\n" +
 	"\x11room_info_changed\x18\x02 \x01(\v2!.messages.ResponseRoomInfoChangedH\x00R\x0froomInfoChanged\x12B\n" +
@@ -1117,8 +2162,18 @@ const file_session_resp_proto_rawDesc = "" +
 	"\x13loaded_count_update\x18\x06 \x01(\v2#.messages.ResponseLoadedCountUpdateH\x00R\x11loadedCountUpdate\x12F\n" +
 	"\x0ein_game_frames\x18\a \x01(\v2\x1e.messages.ResponseInGameFramesH\x00R\finGameFrames\x126\n" +
 	"\bend_game\x18\b \x01(\v2\x19.messages.ResponseEndGameH\x00R\aendGame\x12/\n" +
-	"\x05other\x18\t \x01(\v2\x17.messages.ResponseOtherH\x00R\x05otherB\t\n" +
-	"\apayload\"\xac\x01\n" +
+	"\x05other\x18\t \x01(\v2\x17.messages.ResponseOtherH\x00R\x05other\x128\n" +
+	"\bsnapshot\x18\n" +
+	" \x01(\v2\x1a.messages.ResponseSnapshotH\x00R\bsnapshot\x122\n" +
+	"\x06kicked\x18\v \x01(\v2\x18.messages.ResponseKickedH\x00R\x06kicked\x12B\n" +
+	"\freplay_state\x18\f \x01(\v2\x1d.messages.ResponseReplayStateH\x00R\vreplayState\x122\n" +
+	"\x06desync\x18\r \x01(\v2\x18.messages.ResponseDesyncH\x00R\x06desync\x12W\n" +
+	"\x13chunk_subscriptions\x18\x0e \x01(\v2$.messages.ResponseChunkSubscriptionsH\x00R\x12chunkSubscriptions\x12/\n" +
+	"\x05error\x18\x0f \x01(\v2\x17.messages.ResponseErrorH\x00R\x05error\x12K\n" +
+	"\x0fready_countdown\x18\x10 \x01(\v2 .messages.ResponseReadyCountdownH\x00R\x0ereadyCountdown\x122\n" +
+	"\x06paused\x18\x11 \x01(\v2\x18.messages.ResponsePausedH\x00R\x06paused\x12E\n" +
+	"\rclose_pending\x18\x12 \x01(\v2\x1e.messages.ResponseClosePendingH\x00R\fclosePendingB\t\n" +
+	"\apayload\"\xd7\x01\n" +
 	"\bRoomInfo\x12\x18\n" +
 	"\aRoomKey\x18\x02 \x01(\tR\aRoomKey\x12\x1e\n" +
 	"\n" +
@@ -1126,8 +2181,11 @@ const file_session_resp_proto_rawDesc = "" +
 	"MaxPlayers\x12&\n" +
 	"\x0eCurrentPlayers\x18\x05 \x01(\x05R\x0eCurrentPlayers\x12\x1c\n" +
 	"\tPlayerIDs\x18\x06 \x03(\rR\tPlayerIDs\x12\x17\n" +
-	"\x04data\x18\a \x01(\fH\x00R\x04data\x88\x01\x01B\a\n" +
-	"\x05_data\"\x99\x01\n" +
+	"\x04data\x18\a \x01(\fH\x00R\x04data\x88\x01\x01\x12\x1d\n" +
+	"\aOwnerID\x18\b \x01(\rH\x01R\aOwnerID\x88\x01\x01B\a\n" +
+	"\x05_dataB\n" +
+	"\n" +
+	"\b_OwnerID\"\x99\x01\n" +
 	"\x13ResponseJoinSuccess\x12\x16\n" +
 	"\x06RoomID\x18\x01 \x01(\rR\x06RoomID\x12.\n" +
 	"\bRoomInfo\x18\x04 \x01(\v2\x12.messages.RoomInfoR\bRoomInfo\x12\x12\n" +
@@ -1141,9 +2199,20 @@ const file_session_resp_proto_rawDesc = "" +
 	"\x04fail\x18\x03 \x01(\v2\x1a.messages.ResponseJoinFailH\x00R\x04failB\t\n" +
 	"\apayload\"J\n" +
 	"\x17ResponseRoomInfoChanged\x12/\n" +
-	"\troom_info\x18\x01 \x01(\v2\x12.messages.RoomInfoR\broomInfo\",\n" +
+	"\troom_info\x18\x01 \x01(\v2\x12.messages.RoomInfoR\broomInfo\"W\n" +
 	"\x12ResponseRoomClosed\x12\x16\n" +
-	"\x06Reason\x18\x01 \x01(\tR\x06Reason\"S\n" +
+	"\x06Reason\x18\x01 \x01(\tR\x06Reason\x12)\n" +
+	"\x04code\x18\x02 \x01(\x0e2\x15.messages.CloseReasonR\x04code\"|\n" +
+	"\x14ResponseClosePending\x12)\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x15.messages.CloseReasonR\x04code\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12!\n" +
+	"\fremaining_ms\x18\x03 \x01(\rR\vremainingMs\"R\n" +
+	"\rResponseError\x12'\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x13.messages.ErrorCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"S\n" +
+	"\x0eResponseKicked\x12)\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x15.messages.CloseReasonR\x04code\x12\x16\n" +
+	"\x06Reason\x18\x02 \x01(\tR\x06Reason\"S\n" +
 	"\x13ResponseStageChange\x12\x1a\n" +
 	"\bNewStage\x18\x01 \x01(\rR\bNewStage\x12\x17\n" +
 	"\x04data\x18\x02 \x01(\fH\x00R\x04data\x88\x01\x01B\a\n" +
@@ -1151,25 +2220,53 @@ const file_session_resp_proto_rawDesc = "" +
 	"\x18ResponseReadyCountUpdate\x12(\n" +
 	"\x10ready_player_ids\x18\x01 \x03(\rR\x0ereadyPlayerIds\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\rR\n" +
-	"totalCount\"h\n" +
+	"totalCount\"S\n" +
+	"\x16ResponseReadyCountdown\x12\x16\n" +
+	"\x06active\x18\x01 \x01(\bR\x06active\x12!\n" +
+	"\fremaining_ms\x18\x02 \x01(\rR\vremainingMs\"\x9c\x01\n" +
 	"\x19ResponseLoadedCountUpdate\x12*\n" +
 	"\x11loaded_player_ids\x18\x01 \x03(\rR\x0floadedPlayerIds\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\rR\n" +
-	"totalCount\"R\n" +
+	"totalCount\x122\n" +
+	"\bprogress\x18\x03 \x03(\v2\x16.messages.LoadProgressR\bprogress\"E\n" +
+	"\fLoadProgress\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\rR\bplayerId\x12\x18\n" +
+	"\apercent\x18\x02 \x01(\rR\apercent\"R\n" +
 	"\x0fClientInputData\x12\x10\n" +
 	"\x03uid\x18\x01 \x01(\rR\x03uid\x12\x19\n" +
 	"\bframe_id\x18\x02 \x01(\rR\aframeId\x12\x12\n" +
 	"\x04data\x18\x03 \x01(\fR\x04data\"?\n" +
 	"\x0eWorldEventData\x12\x19\n" +
 	"\bframe_id\x18\x01 \x01(\rR\aframeId\x12\x12\n" +
-	"\x04data\x18\x02 \x01(\fR\x04data\"\x8e\x01\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\xdb\x01\n" +
 	"\tFrameData\x12\x19\n" +
 	"\bframe_id\x18\x01 \x01(\rR\aframeId\x12*\n" +
 	"\x10oldestAckFrameId\x18\x05 \x01(\rR\x10oldestAckFrameId\x12:\n" +
 	"\vinput_array\x18\x02 \x03(\v2\x19.messages.ClientInputDataR\n" +
-	"inputArray\"C\n" +
+	"inputArray\x120\n" +
+	"\x06events\x18\x03 \x03(\v2\x18.messages.WorldEventDataR\x06events\x12\x19\n" +
+	"\bchunk_id\x18\x06 \x01(\rR\achunkId\"r\n" +
 	"\x14ResponseInGameFrames\x12+\n" +
-	"\x06frames\x18\x01 \x03(\v2\x13.messages.FrameDataR\x06frames\"S\n" +
+	"\x06frames\x18\x01 \x03(\v2\x13.messages.FrameDataR\x06frames\x12-\n" +
+	"\x13last_input_frame_id\x18\x02 \x01(\rR\x10lastInputFrameId\"n\n" +
+	"\x10ResponseSnapshot\x12\x19\n" +
+	"\bframe_id\x18\x01 \x01(\rR\aframeId\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12+\n" +
+	"\x06frames\x18\x03 \x03(\v2\x13.messages.FrameDataR\x06frames\"o\n" +
+	"\x0eResponseDesync\x12\x19\n" +
+	"\bframe_id\x18\x01 \x01(\rR\aframeId\x127\n" +
+	"\x06resync\x18\x02 \x01(\v2\x1a.messages.ResponseSnapshotH\x00R\x06resync\x88\x01\x01B\t\n" +
+	"\a_resync\"\x8d\x01\n" +
+	"\x1aResponseChunkSubscriptions\x12\x1b\n" +
+	"\tchunk_ids\x18\x01 \x03(\rR\bchunkIds\x12,\n" +
+	"\x12rejected_chunk_ids\x18\x02 \x03(\rR\x10rejectedChunkIds\x12$\n" +
+	"\x0esince_frame_id\x18\x03 \x01(\rR\fsinceFrameId\"\x9c\x01\n" +
+	"\x13ResponseReplayState\x12\x16\n" +
+	"\x06paused\x18\x01 \x01(\bR\x06paused\x12\x14\n" +
+	"\x05speed\x18\x02 \x01(\x02R\x05speed\x12\x19\n" +
+	"\bframe_id\x18\x03 \x01(\rR\aframeId\x12\"\n" +
+	"\rlast_frame_id\x18\x04 \x01(\rR\vlastFrameId\x12\x18\n" +
+	"\arewound\x18\x05 \x01(\bR\arewound\"S\n" +
 	"\x0fResponseEndGame\x12\x1e\n" +
 	"\n" +
 	"StatusCode\x18\x01 \x01(\rR\n" +
@@ -1178,7 +2275,37 @@ const file_session_resp_proto_rawDesc = "" +
 	"\x05_data\"1\n" +
 	"\rResponseOther\x12\x17\n" +
 	"\x04data\x18\x01 \x01(\fH\x00R\x04data\x88\x01\x01B\a\n" +
-	"\x05_dataB\rZ\v./;messagesb\x06proto3"
+	"\x05_data\"\xeb\x01\n" +
+	"\x0eResponsePaused\x12\x16\n" +
+	"\x06paused\x18\x01 \x01(\bR\x06paused\x12\x19\n" +
+	"\bframe_id\x18\x02 \x01(\rR\aframeId\x12\x1b\n" +
+	"\tplayer_id\x18\x03 \x01(\rR\bplayerId\x12-\n" +
+	"\x06reason\x18\x04 \x01(\x0e2\x15.messages.PauseReasonR\x06reason\x12\x14\n" +
+	"\x05votes\x18\x05 \x03(\rR\x05votes\x12!\n" +
+	"\fvotes_needed\x18\x06 \x01(\rR\vvotesNeeded\x12!\n" +
+	"\fremaining_ms\x18\a \x01(\rR\vremainingMs*\xa1\x02\n" +
+	"\vCloseReason\x12\x1c\n" +
+	"\x18CLOSE_REASON_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11CLOSE_REASON_IDLE\x10\x01\x12\x1c\n" +
+	"\x18CLOSE_REASON_HOST_CLOSED\x10\x02\x12\x1b\n" +
+	"\x17CLOSE_REASON_GAME_ENDED\x10\x03\x12 \n" +
+	"\x1cCLOSE_REASON_SERVER_SHUTDOWN\x10\x04\x12\x17\n" +
+	"\x13CLOSE_REASON_KICKED\x10\x05\x12 \n" +
+	"\x1cCLOSE_REASON_DUPLICATE_LOGIN\x10\x06\x12 \n" +
+	"\x1cCLOSE_REASON_SLOW_CONNECTION\x10\a\x12#\n" +
+	"\x1fCLOSE_REASON_PROTOCOL_VIOLATION\x10\b*\xaa\x01\n" +
+	"\tErrorCode\x12\x1a\n" +
+	"\x16ERROR_CODE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18ERROR_CODE_ILLEGAL_STAGE\x10\x01\x12!\n" +
+	"\x1dERROR_CODE_ILLEGAL_TRANSITION\x10\x02\x12 \n" +
+	"\x1cERROR_CODE_PERMISSION_DENIED\x10\x03\x12\x1e\n" +
+	"\x1aERROR_CODE_INVALID_REQUEST\x10\x04*\xbb\x01\n" +
+	"\vPauseReason\x12\x1c\n" +
+	"\x18PAUSE_REASON_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bPAUSE_REASON_PLAYER_REQUEST\x10\x01\x12$\n" +
+	" PAUSE_REASON_PLAYER_DISCONNECTED\x10\x02\x12$\n" +
+	" PAUSE_REASON_DISCONNECT_RESOLVED\x10\x03\x12!\n" +
+	"\x1dPAUSE_REASON_BUDGET_EXHAUSTED\x10\x04B\rZ\v./;messagesb\x06proto3"
 
 var (
 	file_session_resp_proto_rawDescOnce sync.Once
@@ -1192,46 +2319,78 @@ func file_session_resp_proto_rawDescGZIP() []byte {
 	return file_session_resp_proto_rawDescData
 }
 
-var file_session_resp_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_session_resp_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_session_resp_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_session_resp_proto_goTypes = []any{
-	(*SessionResponse)(nil),           // 0: messages.SessionResponse
-	(*RoomInfo)(nil),                  // 1: messages.RoomInfo
-	(*ResponseJoinSuccess)(nil),       // 2: messages.ResponseJoinSuccess
-	(*ResponseJoinFail)(nil),          // 3: messages.ResponseJoinFail
-	(*ResponseJoin)(nil),              // 4: messages.ResponseJoin
-	(*ResponseRoomInfoChanged)(nil),   // 5: messages.ResponseRoomInfoChanged
-	(*ResponseRoomClosed)(nil),        // 6: messages.ResponseRoomClosed
-	(*ResponseStageChange)(nil),       // 7: messages.ResponseStageChange
-	(*ResponseReadyCountUpdate)(nil),  // 8: messages.ResponseReadyCountUpdate
-	(*ResponseLoadedCountUpdate)(nil), // 9: messages.ResponseLoadedCountUpdate
-	(*ClientInputData)(nil),           // 10: messages.ClientInputData
-	(*WorldEventData)(nil),            // 11: messages.WorldEventData
-	(*FrameData)(nil),                 // 12: messages.FrameData
-	(*ResponseInGameFrames)(nil),      // 13: messages.ResponseInGameFrames
-	(*ResponseEndGame)(nil),           // 14: messages.ResponseEndGame
-	(*ResponseOther)(nil),             // 15: messages.ResponseOther
+	(CloseReason)(0),                   // 0: messages.CloseReason
+	(ErrorCode)(0),                     // 1: messages.ErrorCode
+	(PauseReason)(0),                   // 2: messages.PauseReason
+	(*SessionResponse)(nil),            // 3: messages.SessionResponse
+	(*RoomInfo)(nil),                   // 4: messages.RoomInfo
+	(*ResponseJoinSuccess)(nil),        // 5: messages.ResponseJoinSuccess
+	(*ResponseJoinFail)(nil),           // 6: messages.ResponseJoinFail
+	(*ResponseJoin)(nil),               // 7: messages.ResponseJoin
+	(*ResponseRoomInfoChanged)(nil),    // 8: messages.ResponseRoomInfoChanged
+	(*ResponseRoomClosed)(nil),         // 9: messages.ResponseRoomClosed
+	(*ResponseClosePending)(nil),       // 10: messages.ResponseClosePending
+	(*ResponseError)(nil),              // 11: messages.ResponseError
+	(*ResponseKicked)(nil),             // 12: messages.ResponseKicked
+	(*ResponseStageChange)(nil),        // 13: messages.ResponseStageChange
+	(*ResponseReadyCountUpdate)(nil),   // 14: messages.ResponseReadyCountUpdate
+	(*ResponseReadyCountdown)(nil),     // 15: messages.ResponseReadyCountdown
+	(*ResponseLoadedCountUpdate)(nil),  // 16: messages.ResponseLoadedCountUpdate
+	(*LoadProgress)(nil),               // 17: messages.LoadProgress
+	(*ClientInputData)(nil),            // 18: messages.ClientInputData
+	(*WorldEventData)(nil),             // 19: messages.WorldEventData
+	(*FrameData)(nil),                  // 20: messages.FrameData
+	(*ResponseInGameFrames)(nil),       // 21: messages.ResponseInGameFrames
+	(*ResponseSnapshot)(nil),           // 22: messages.ResponseSnapshot
+	(*ResponseDesync)(nil),             // 23: messages.ResponseDesync
+	(*ResponseChunkSubscriptions)(nil), // 24: messages.ResponseChunkSubscriptions
+	(*ResponseReplayState)(nil),        // 25: messages.ResponseReplayState
+	(*ResponseEndGame)(nil),            // 26: messages.ResponseEndGame
+	(*ResponseOther)(nil),              // 27: messages.ResponseOther
+	(*ResponsePaused)(nil),             // 28: messages.ResponsePaused
 }
 var file_session_resp_proto_depIdxs = []int32{
-	4,  // 0: messages.SessionResponse.join:type_name -> messages.ResponseJoin
-	5,  // 1: messages.SessionResponse.room_info_changed:type_name -> messages.ResponseRoomInfoChanged
-	7,  // 2: messages.SessionResponse.stage_change:type_name -> messages.ResponseStageChange
-	6,  // 3: messages.SessionResponse.room_closed:type_name -> messages.ResponseRoomClosed
-	8,  // 4: messages.SessionResponse.ready_count_update:type_name -> messages.ResponseReadyCountUpdate
-	9,  // 5: messages.SessionResponse.loaded_count_update:type_name -> messages.ResponseLoadedCountUpdate
-	13, // 6: messages.SessionResponse.in_game_frames:type_name -> messages.ResponseInGameFrames
-	14, // 7: messages.SessionResponse.end_game:type_name -> messages.ResponseEndGame
-	15, // 8: messages.SessionResponse.other:type_name -> messages.ResponseOther
-	1,  // 9: messages.ResponseJoinSuccess.RoomInfo:type_name -> messages.RoomInfo
-	2,  // 10: messages.ResponseJoin.success:type_name -> messages.ResponseJoinSuccess
-	3,  // 11: messages.ResponseJoin.fail:type_name -> messages.ResponseJoinFail
-	1,  // 12: messages.ResponseRoomInfoChanged.room_info:type_name -> messages.RoomInfo
-	10, // 13: messages.FrameData.input_array:type_name -> messages.ClientInputData
-	12, // 14: messages.ResponseInGameFrames.frames:type_name -> messages.FrameData
-	15, // [15:15] is the sub-list for method output_type
-	15, // [15:15] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	7,  // 0: messages.SessionResponse.join:type_name -> messages.ResponseJoin
+	8,  // 1: messages.SessionResponse.room_info_changed:type_name -> messages.ResponseRoomInfoChanged
+	13, // 2: messages.SessionResponse.stage_change:type_name -> messages.ResponseStageChange
+	9,  // 3: messages.SessionResponse.room_closed:type_name -> messages.ResponseRoomClosed
+	14, // 4: messages.SessionResponse.ready_count_update:type_name -> messages.ResponseReadyCountUpdate
+	16, // 5: messages.SessionResponse.loaded_count_update:type_name -> messages.ResponseLoadedCountUpdate
+	21, // 6: messages.SessionResponse.in_game_frames:type_name -> messages.ResponseInGameFrames
+	26, // 7: messages.SessionResponse.end_game:type_name -> messages.ResponseEndGame
+	27, // 8: messages.SessionResponse.other:type_name -> messages.ResponseOther
+	22, // 9: messages.SessionResponse.snapshot:type_name -> messages.ResponseSnapshot
+	12, // 10: messages.SessionResponse.kicked:type_name -> messages.ResponseKicked
+	25, // 11: messages.SessionResponse.replay_state:type_name -> messages.ResponseReplayState
+	23, // 12: messages.SessionResponse.desync:type_name -> messages.ResponseDesync
+	24, // 13: messages.SessionResponse.chunk_subscriptions:type_name -> messages.ResponseChunkSubscriptions
+	11, // 14: messages.SessionResponse.error:type_name -> messages.ResponseError
+	15, // 15: messages.SessionResponse.ready_countdown:type_name -> messages.ResponseReadyCountdown
+	28, // 16: messages.SessionResponse.paused:type_name -> messages.ResponsePaused
+	10, // 17: messages.SessionResponse.close_pending:type_name -> messages.ResponseClosePending
+	4,  // 18: messages.ResponseJoinSuccess.RoomInfo:type_name -> messages.RoomInfo
+	5,  // 19: messages.ResponseJoin.success:type_name -> messages.ResponseJoinSuccess
+	6,  // 20: messages.ResponseJoin.fail:type_name -> messages.ResponseJoinFail
+	4,  // 21: messages.ResponseRoomInfoChanged.room_info:type_name -> messages.RoomInfo
+	0,  // 22: messages.ResponseRoomClosed.code:type_name -> messages.CloseReason
+	0,  // 23: messages.ResponseClosePending.code:type_name -> messages.CloseReason
+	1,  // 24: messages.ResponseError.code:type_name -> messages.ErrorCode
+	0,  // 25: messages.ResponseKicked.code:type_name -> messages.CloseReason
+	17, // 26: messages.ResponseLoadedCountUpdate.progress:type_name -> messages.LoadProgress
+	18, // 27: messages.FrameData.input_array:type_name -> messages.ClientInputData
+	19, // 28: messages.FrameData.events:type_name -> messages.WorldEventData
+	20, // 29: messages.ResponseInGameFrames.frames:type_name -> messages.FrameData
+	20, // 30: messages.ResponseSnapshot.frames:type_name -> messages.FrameData
+	22, // 31: messages.ResponseDesync.resync:type_name -> messages.ResponseSnapshot
+	2,  // 32: messages.ResponsePaused.reason:type_name -> messages.PauseReason
+	33, // [33:33] is the sub-list for method output_type
+	33, // [33:33] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_session_resp_proto_init() }
@@ -1249,27 +2408,38 @@ func file_session_resp_proto_init() {
 		(*SessionResponse_InGameFrames)(nil),
 		(*SessionResponse_EndGame)(nil),
 		(*SessionResponse_Other)(nil),
+		(*SessionResponse_Snapshot)(nil),
+		(*SessionResponse_Kicked)(nil),
+		(*SessionResponse_ReplayState)(nil),
+		(*SessionResponse_Desync)(nil),
+		(*SessionResponse_ChunkSubscriptions)(nil),
+		(*SessionResponse_Error)(nil),
+		(*SessionResponse_ReadyCountdown)(nil),
+		(*SessionResponse_Paused)(nil),
+		(*SessionResponse_ClosePending)(nil),
 	}
 	file_session_resp_proto_msgTypes[1].OneofWrappers = []any{}
 	file_session_resp_proto_msgTypes[4].OneofWrappers = []any{
 		(*ResponseJoin_Success)(nil),
 		(*ResponseJoin_Fail)(nil),
 	}
-	file_session_resp_proto_msgTypes[7].OneofWrappers = []any{}
-	file_session_resp_proto_msgTypes[14].OneofWrappers = []any{}
-	file_session_resp_proto_msgTypes[15].OneofWrappers = []any{}
+	file_session_resp_proto_msgTypes[10].OneofWrappers = []any{}
+	file_session_resp_proto_msgTypes[20].OneofWrappers = []any{}
+	file_session_resp_proto_msgTypes[23].OneofWrappers = []any{}
+	file_session_resp_proto_msgTypes[24].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_session_resp_proto_rawDesc), len(file_session_resp_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   16,
+			NumEnums:      3,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_session_resp_proto_goTypes,
 		DependencyIndexes: file_session_resp_proto_depIdxs,
+		EnumInfos:         file_session_resp_proto_enumTypes,
 		MessageInfos:      file_session_resp_proto_msgTypes,
 	}.Build()
 	File_session_resp_proto = out.File
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// ViolationWindow 违规计数的统计窗口，窗口结束后重新计数
+const ViolationWindow = 10 * time.Second
+
+// ErrorReplyInterval 向同一客户端回复请求被拒绝原因的最小间隔，避免违规请求换来同样数量的回复
+const ErrorReplyInterval = time.Second
+
+// Guard 客户端请求的限流与违规计数
+// 具体的限制由房间传入，Guard 只保存每个客户端的状态
+type Guard struct {
+	mu sync.Mutex
+	// 令牌桶
+	tokens     float64
+	lastRefill time.Time
+	// 当前窗口内的违规次数
+	violations  int
+	windowStart time.Time
+	// 累计违规次数
+	total uint64
+	// 上一次回复请求被拒绝原因的时间
+	lastErrorReply time.Time
+}
+
+// Allow 按每秒 rate 个请求、最多积攒 1 秒的令牌桶判断是否允许一个请求，rate 为 0 时不限流
+func (g *Guard) Allow(now time.Time, rate float64) bool {
+	if rate <= 0 {
+		return true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.lastRefill.IsZero() {
+		g.tokens = rate
+	} else {
+		g.tokens = min(rate, g.tokens+now.Sub(g.lastRefill).Seconds()*rate)
+	}
+	g.lastRefill = now
+	if g.tokens < 1 {
+		return false
+	}
+	g.tokens--
+	return true
+}
+
+// Violate 记录一次违规，返回当前窗口内的违规次数
+func (g *Guard) Violate(now time.Time) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollWindow(now)
+	g.violations++
+	g.total++
+	return g.violations
+}
+
+// Violations 当前窗口内的违规次数
+func (g *Guard) Violations(now time.Time) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollWindow(now)
+	return g.violations
+}
+
+// AllowErrorReply 距离上一次回复是否已超过 ErrorReplyInterval，允许时记录本次回复的时间
+func (g *Guard) AllowErrorReply(now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.lastErrorReply.IsZero() && now.Sub(g.lastErrorReply) < ErrorReplyInterval {
+		return false
+	}
+	g.lastErrorReply = now
+	return true
+}
+
+// TotalViolations 累计违规次数
+func (g *Guard) TotalViolations() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.total
+}
+
+// rollWindow 统计窗口结束时重新计数，调用方需已持有 mu
+func (g *Guard) rollWindow(now time.Time) {
+	if now.Sub(g.windowStart) >= ViolationWindow {
+		g.windowStart = now
+		g.violations = 0
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuardAllowRefillsTokens(t *testing.T) {
+	var g Guard
+	now := time.Unix(1000, 0)
+	for i := 0; i < 2; i++ {
+		if !g.Allow(now, 2) {
+			t.Fatalf("request %d rejected within the initial burst", i)
+		}
+	}
+	if g.Allow(now, 2) {
+		t.Fatal("request beyond the burst was allowed")
+	}
+	if !g.Allow(now.Add(500*time.Millisecond), 2) {
+		t.Fatal("request rejected after a token was refilled")
+	}
+	if !g.Allow(now, 0) {
+		t.Fatal("rate 0 must not limit requests")
+	}
+}
+
+func TestGuardViolationWindow(t *testing.T) {
+	var g Guard
+	now := time.Unix(1000, 0)
+	g.Violate(now)
+	if got := g.Violate(now.Add(time.Second)); got != 2 {
+		t.Fatalf("violations in window = %d, want 2", got)
+	}
+	if got := g.Violations(now.Add(ViolationWindow)); got != 0 {
+		t.Fatalf("violations after the window = %d, want 0", got)
+	}
+	if got := g.TotalViolations(); got != 2 {
+		t.Fatalf("total violations = %d, want 2", got)
+	}
+}
+
+func TestGuardAllowErrorReply(t *testing.T) {
+	var g Guard
+	now := time.Unix(1000, 0)
+	if !g.AllowErrorReply(now) {
+		t.Fatal("first error reply was suppressed")
+	}
+	if g.AllowErrorReply(now.Add(ErrorReplyInterval / 2)) {
+		t.Fatal("error reply within the interval was allowed")
+	}
+	if !g.AllowErrorReply(now.Add(ErrorReplyInterval)) {
+		t.Fatal("error reply after the interval was suppressed")
+	}
+}
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"lockstep-core/src/pkg/lockstep/session"
+	"sync"
+	"time"
+)
+
+// DefaultOutboxCapacity 出站队列默认容量
+const DefaultOutboxCapacity = 256
+
+// outboundMessage 等待发送的消息
+type outboundMessage struct {
+	data     []byte
+	reliable bool
+	// 非空时以 data 作为最后一条消息关闭指定的会话
+	close *sessionClose
+}
+
+// sessionClose 发送最后一条消息后关闭会话的请求
+type sessionClose struct {
+	sess   session.ISession
+	code   uint32
+	reason string
+}
+
+// OutboxStats 出站队列的积压统计，用于监控
+type OutboxStats struct {
+	// 当前排队的帧数据报与控制消息数量
+	QueuedDatagrams int
+	QueuedControl   int
+	// 排队消息数量的历史最大值
+	HighWater int
+	// 已发送与因队列已满被丢弃的消息数量
+	Sent    uint64
+	Dropped uint64
+	// 开始持续饱和的时间，未饱和时为零值
+	SaturatedSince time.Time
+}
+
+// Outbox 客户端的有界出站队列，由至多一个写入 goroutine 按入队顺序发送
+// 帧数据报在队列已满时丢弃最旧的一条，之后的帧包会冗余携带其中的帧；
+// 控制消息从不丢弃，积压超过容量时队列视为饱和
+// 关闭会话同样作为队列中的一项，保证最后一条消息排在此前的消息之后，且会话只由写入 goroutine 访问
+// 写入 goroutine 在首条消息入队时启动，队列为空时等待，队列停止后退出
+type Outbox struct {
+	mu       sync.Mutex
+	queue    []outboundMessage
+	capacity int
+	// 队列中的帧数据报数量
+	datagrams int
+	// 是否有写入 goroutine 正在运行
+	writing bool
+	// 有消息入队或队列停止时唤醒写入 goroutine
+	wake chan struct{}
+	// 已停止接受新消息，写入 goroutine 发送完剩余消息后退出
+	stopped bool
+	// 已排入关闭当前会话的最后一条消息
+	closing bool
+
+	highWater      int
+	sent           uint64
+	dropped        uint64
+	saturatedSince time.Time
+}
+
+// NewOutbox 创建容量为 capacity 的出站队列
+func NewOutbox(capacity int) *Outbox {
+	return &Outbox{capacity: max(1, capacity), wake: make(chan struct{}, 1)}
+}
+
+// SetCapacity 修改队列容量，已排队的消息不受影响
+func (o *Outbox) SetCapacity(capacity int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.capacity = max(1, capacity)
+}
+
+// push 将消息加入队列，需要启动写入 goroutine 时返回 true
+// final 为 true 时该消息是关闭当前会话前的最后一条，此后入队的消息均被丢弃
+func (o *Outbox) push(msg outboundMessage, final bool) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.stopped {
+		o.dropped++
+		return false
+	}
+	if !msg.reliable {
+		if o.datagrams >= o.capacity {
+			o.dropOldestDatagram()
+		}
+		o.datagrams++
+	}
+	o.queue = append(o.queue, msg)
+	o.highWater = max(o.highWater, len(o.queue))
+	o.updateSaturation()
+	if final {
+		o.stopped = true
+		o.closing = true
+	}
+	o.signal()
+
+	if o.writing {
+		return false
+	}
+	o.writing = true
+	return true
+}
+
+// dropOldestDatagram 丢弃队列中最旧的帧数据报
+func (o *Outbox) dropOldestDatagram() {
+	for i, msg := range o.queue {
+		if !msg.reliable {
+			o.queue = append(o.queue[:i], o.queue[i+1:]...)
+			o.datagrams--
+			o.dropped++
+			return
+		}
+	}
+}
+
+// signal 唤醒写入 goroutine，调用方需已持有 mu
+func (o *Outbox) signal() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next 等待并取出下一条要发送的消息
+// 队列已停止且为空时返回 false，写入 goroutine 应退出
+func (o *Outbox) next() (outboundMessage, bool) {
+	for {
+		o.mu.Lock()
+		if len(o.queue) > 0 {
+			msg := o.queue[0]
+			o.queue[0] = outboundMessage{}
+			o.queue = o.queue[1:]
+			if !msg.reliable {
+				o.datagrams--
+			}
+			o.sent++
+			o.updateSaturation()
+			o.mu.Unlock()
+			return msg, true
+		}
+		if o.stopped {
+			o.writing = false
+			o.queue = nil
+			o.mu.Unlock()
+			return outboundMessage{}, false
+		}
+		o.mu.Unlock()
+		<-o.wake
+	}
+}
+
+// updateSaturation 帧数据报队列已满或控制消息积压超过容量时为饱和，调用方需已持有 mu
+func (o *Outbox) updateSaturation() {
+	saturated := o.datagrams >= o.capacity || len(o.queue)-o.datagrams > o.capacity
+	if !saturated {
+		o.saturatedSince = time.Time{}
+	} else if o.saturatedSince.IsZero() {
+		o.saturatedSince = time.Now()
+	}
+}
+
+// SaturatedFor 队列已持续饱和的时间，未饱和时为 0
+func (o *Outbox) SaturatedFor(now time.Time) time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.saturatedSince.IsZero() {
+		return 0
+	}
+	return now.Sub(o.saturatedSince)
+}
+
+// Clear 丢弃所有排队的消息并重新接受新消息，例如换入重连的新会话时
+// 关闭旧会话的请求不会被丢弃
+func (o *Outbox) Clear() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.discard()
+	o.stopped = false
+	o.closing = false
+}
+
+// Stop 丢弃排队的消息并停止接受新消息，写入 goroutine 随之退出
+// 已排入关闭当前会话的最后一条消息时不做任何事并返回 false，该消息仍会被发送
+func (o *Outbox) Stop() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closing {
+		return false
+	}
+	o.discard()
+	o.stopped = true
+	o.signal()
+	return true
+}
+
+// discard 丢弃除关闭会话请求以外的所有排队消息，调用方需已持有 mu
+func (o *Outbox) discard() {
+	kept := o.queue[:0]
+	for _, msg := range o.queue {
+		if msg.close != nil {
+			kept = append(kept, msg)
+		} else {
+			o.dropped++
+		}
+	}
+	clear(o.queue[len(kept):])
+	o.queue = kept
+	o.datagrams = 0
+	o.saturatedSince = time.Time{}
+}
+
+// Stats 获取队列的积压统计
+func (o *Outbox) Stats() OutboxStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return OutboxStats{
+		QueuedDatagrams: o.datagrams,
+		QueuedControl:   len(o.queue) - o.datagrams,
+		HighWater:       o.highWater,
+		Sent:            o.sent,
+		Dropped:         o.dropped,
+		SaturatedSince:  o.saturatedSince,
+	}
+}
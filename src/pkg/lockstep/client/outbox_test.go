@@ -0,0 +1,212 @@
+package client
+
+import (
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSession 记录发送内容与关闭请求的会话
+type recordingSession struct {
+	mu        sync.Mutex
+	events    []string
+	connected bool
+	closed    chan struct{}
+}
+
+func newRecordingSession() *recordingSession {
+	return &recordingSession{connected: true, closed: make(chan struct{})}
+}
+
+func (s *recordingSession) record(event string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *recordingSession) Events() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.events)
+}
+
+func (s *recordingSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.connected {
+		s.connected = false
+		close(s.closed)
+	}
+	return nil
+}
+
+func (s *recordingSession) CloseWithError(code uint32, reason string) error {
+	return s.Close()
+}
+
+func (s *recordingSession) CloseWithMessage(data []byte, code uint32, reason string) error {
+	s.record("close:" + string(data))
+	return s.Close()
+}
+
+func (s *recordingSession) IsConnected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connected
+}
+
+func (s *recordingSession) SendDatagram(data []byte) error {
+	s.record("datagram:" + string(data))
+	return nil
+}
+
+func (s *recordingSession) ReceiveDatagram() ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *recordingSession) SendReliable(data []byte) error {
+	s.record("reliable:" + string(data))
+	return nil
+}
+
+func (s *recordingSession) ReceiveReliable() ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *recordingSession) GetRemoteAddr() net.Addr {
+	return &net.UDPAddr{}
+}
+
+// waitClosed 等待会话被关闭
+func waitClosed(t *testing.T, s *recordingSession) {
+	t.Helper()
+	select {
+	case <-s.closed:
+	case <-time.After(time.Second):
+		t.Fatal("session was not closed")
+	}
+}
+
+// waitEvents 等待会话记录到 n 个事件
+func waitEvents(t *testing.T, s *recordingSession, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(s.Events()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("session recorded %v, want %d events", s.Events(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitWriterExit 等待写入 goroutine 退出
+func waitWriterExit(t *testing.T, o *Outbox) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		o.mu.Lock()
+		writing := o.writing
+		o.mu.Unlock()
+		if !writing {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("writer goroutine did not exit")
+}
+
+func TestOutboxDropsOldestDatagram(t *testing.T) {
+	o := NewOutbox(2)
+	o.push(outboundMessage{data: []byte("d1")}, false)
+	o.push(outboundMessage{data: []byte("r1"), reliable: true}, false)
+	o.push(outboundMessage{data: []byte("d2")}, false)
+	o.push(outboundMessage{data: []byte("d3")}, false)
+
+	stats := o.Stats()
+	if stats.Dropped != 1 || stats.QueuedDatagrams != 2 || stats.QueuedControl != 1 {
+		t.Fatalf("stats = %+v, want 1 dropped, 2 datagrams and 1 control message queued", stats)
+	}
+	if o.SaturatedFor(time.Now().Add(time.Second)) == 0 {
+		t.Fatal("full datagram queue is not reported as saturated")
+	}
+
+	o.Stop()
+	if msg, ok := o.next(); ok {
+		t.Fatalf("stopped outbox returned %q", msg.data)
+	}
+}
+
+func TestOutboxSendsInOrder(t *testing.T) {
+	o := NewOutbox(4)
+	for _, data := range []string{"a", "b", "c"} {
+		o.push(outboundMessage{data: []byte(data), reliable: true}, false)
+	}
+	for _, want := range []string{"a", "b", "c"} {
+		msg, ok := o.next()
+		if !ok || string(msg.data) != want {
+			t.Fatalf("next = %q, %v, want %q", msg.data, ok, want)
+		}
+	}
+	if got := o.Stats().Sent; got != 3 {
+		t.Fatalf("sent = %d, want 3", got)
+	}
+}
+
+func TestClientCloseWithMessageAfterQueuedMessages(t *testing.T) {
+	sess := newRecordingSession()
+	c := NewClient(1, sess, nil)
+	c.WriteReliable([]byte("a"))
+	c.Write([]byte("b"))
+	c.CloseWithMessage([]byte("bye"), 1, "kicked")
+	c.WriteReliable([]byte("late"))
+	// 已安排的最后一条消息不会被 Close 丢弃
+	c.Close()
+
+	waitClosed(t, sess)
+	waitWriterExit(t, c.Outbox)
+	want := []string{"reliable:a", "datagram:b", "close:bye"}
+	if got := sess.Events(); !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+func TestClientRetireSessionKeepsServingNewSession(t *testing.T) {
+	old, current := newRecordingSession(), newRecordingSession()
+	c := NewClient(1, old, nil)
+	c.SwapSession(current)
+	c.RetireSession(old, []byte("replaced"), 1, "duplicate login")
+	c.WriteReliable([]byte("state"))
+
+	waitClosed(t, old)
+	if got, want := old.Events(), []string{"close:replaced"}; !slices.Equal(got, want) {
+		t.Fatalf("old session events = %v, want %v", got, want)
+	}
+	waitEvents(t, current, 1)
+	c.Close()
+	waitClosed(t, current)
+	waitWriterExit(t, c.Outbox)
+	if got, want := current.Events(), []string{"reliable:state"}; !slices.Equal(got, want) {
+		t.Fatalf("new session events = %v, want %v", got, want)
+	}
+}
+
+func TestClientReopensOutboxAfterReconnect(t *testing.T) {
+	first, second := newRecordingSession(), newRecordingSession()
+	c := NewClient(1, first, nil)
+	c.CloseWithMessage([]byte("slow"), 1, "slow connection")
+	waitClosed(t, first)
+	waitWriterExit(t, c.Outbox)
+
+	c.SwapSession(second)
+	c.WriteReliable([]byte("welcome back"))
+	waitEvents(t, second, 1)
+	c.Close()
+	waitClosed(t, second)
+	waitWriterExit(t, c.Outbox)
+	if got, want := second.Events(), []string{"reliable:welcome back"}; !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
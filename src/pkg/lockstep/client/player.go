@@ -6,6 +6,8 @@ import (
 	lockstep_sync "lockstep-core/src/pkg/lockstep/sync"
 	"log"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // ClientMessage
@@ -56,10 +58,20 @@ func (p *ClientMessagePool) ReleasePlayerMessage(msg *ClientMessage) {
 // 已和游戏世界逻辑解耦
 type Client struct {
 	// 客户端会话
+	// 重连时会被替换，跨 goroutine 读取请使用 GetSession
 	Session session.ISession
+	// 保护 Session 的替换
+	sessionMu sync.RWMutex
+	// 会话代数，每次替换会话时递增，用于识别过期的会话服务循环
+	sessionGen atomic.Uint32
 	// 持有对 "发送消息到服务器的通道" 的引用
 	SendChan chan<- *ClientMessage
 
+	// 出站队列，所有发往客户端的消息经此发送
+	Outbox *Outbox
+	// 请求限流与违规计数
+	Guard Guard
+
 	// 共享data池以节省开销
 	*ClientMessagePool
 	// lockstep
@@ -68,8 +80,16 @@ type Client struct {
 	// 房间Life Cycle生命周期相关状态
 	IsReady  bool // 是否准备好
 	IsLoaded bool // 是否加载完毕
+	// 加载进度百分比，加载完毕时为 100
+	LoadProgress uint32
+	// 本场对局已用的暂停时长
+	PauseUsed time.Duration
 
 	IsReconnected bool // 是否为重连玩家
+	IsSpectator   bool // 是否为观战者，观战者只接收帧而不能提交输入
+
+	// 断线时间，仅在断线等待重连状态下有效
+	DisconnectedAt time.Time
 	// 游戏数据 (用于防作弊验证)
 	// Deprecated, 在游戏世界中做验证
 	// LastEnergySum  int32 // 上一次用户的能量总和
@@ -83,6 +103,7 @@ func NewClient(uid uint32, sess session.ISession, sendChan chan<- *ClientMessage
 		IsReady:           false,
 		IsLoaded:          false,
 		SendChan:          sendChan,
+		Outbox:            NewOutbox(DefaultOutboxCapacity),
 		ClientMessagePool: NewClientMessagePool(),
 		ClientSyncData:    *lockstep_sync.NewClientSyncData(uid),
 	}
@@ -92,6 +113,8 @@ func NewClient(uid uint32, sess session.ISession, sendChan chan<- *ClientMessage
 func (p *Client) ResetData() {
 	p.IsReady = false
 	p.IsLoaded = false
+	p.LoadProgress = 0
+	p.PauseUsed = 0
 	p.ClientSyncData.Reset()
 }
 
@@ -100,17 +123,132 @@ func (p *Client) GetID() uint32 {
 	return p.ClientSyncData.ID
 }
 
-// Write 写入要发送给客户端的消息
+// GetSession 并发安全地获取当前会话
+func (p *Client) GetSession() session.ISession {
+	p.sessionMu.RLock()
+	defer p.sessionMu.RUnlock()
+	return p.Session
+}
+
+// SessionGeneration 获取当前会话代数
+func (p *Client) SessionGeneration() uint32 {
+	return p.sessionGen.Load()
+}
+
+// SwapSession 以重连的新会话替换旧会话，并恢复为在线状态
+// 玩家的准备、加载与帧同步状态均被保留
+func (p *Client) SwapSession(sess session.ISession) {
+	p.sessionMu.Lock()
+	p.Session = sess
+	p.sessionMu.Unlock()
+
+	p.sessionGen.Add(1)
+	// 发往旧会话的消息已无意义，重连后会重新收到完整的房间状态
+	p.Outbox.Clear()
+	p.IsReconnected = true
+	p.DisconnectedAt = time.Time{}
+	p.SetState(lockstep_sync.PlayerStateConnected)
+}
+
+// Write 以数据报写入要发送给客户端的消息，不保证送达，用于帧数据
+// 消息进入出站队列后立即返回，队列已满时丢弃最旧的数据报
 func (p *Client) Write(data []byte) {
-	if p == nil || p.Session == nil {
+	p.enqueue(data, false)
+}
+
+// WriteReliable 通过可靠有序的通道写入要发送给客户端的控制消息
+// 消息进入出站队列后立即返回，控制消息不会被丢弃
+func (p *Client) WriteReliable(data []byte) {
+	p.enqueue(data, true)
+}
+
+// CloseWithMessage 在已排队的消息之后发送最后一条可靠消息并关闭当前会话
+// 此后写入的消息均被丢弃，直到换入重连的新会话
+func (p *Client) CloseWithMessage(data []byte, code uint32, reason string) {
+	sess := p.GetSession()
+	if sess == nil {
+		return
+	}
+	p.push(outboundMessage{data: data, reliable: true, close: &sessionClose{sess: sess, code: code, reason: reason}}, true)
+}
+
+// RetireSession 在已排队的消息之后发送最后一条可靠消息并关闭已被替换的旧会话
+// 在 SwapSession 之后调用，出站队列继续服务新会话
+func (p *Client) RetireSession(old session.ISession, data []byte, code uint32, reason string) {
+	p.push(outboundMessage{data: data, reliable: true, close: &sessionClose{sess: old, code: code, reason: reason}}, false)
+}
+
+// Close 丢弃排队的消息并立即关闭当前会话，写入 goroutine 随之退出
+// 已通过 CloseWithMessage 安排关闭时不做任何事，最后一条消息仍会送出
+func (p *Client) Close() {
+	if !p.Outbox.Stop() {
+		return
+	}
+	if sess := p.GetSession(); sess != nil && sess.IsConnected() {
+		sess.Close()
+	}
+}
+
+// enqueue 将消息加入出站队列
+func (p *Client) enqueue(data []byte, reliable bool) {
+	if p == nil {
+		log.Printf("🔴 Cannot write message: player or context is nil")
+		return
+	}
+	p.push(outboundMessage{data: data, reliable: reliable}, false)
+}
+
+// push 将消息加入出站队列，必要时启动写入 goroutine
+func (p *Client) push(msg outboundMessage, final bool) {
+	if p.Outbox.push(msg, final) {
+		go p.drain()
+	}
+}
+
+// drain 写入 goroutine，每个客户端至多一个
+// 按顺序发送出站队列中的消息，队列为空时等待，出站队列停止后退出
+func (p *Client) drain() {
+	for {
+		msg, ok := p.Outbox.next()
+		if !ok {
+			return
+		}
+		if msg.close != nil {
+			p.closeSession(msg)
+			continue
+		}
+		p.send(msg)
+	}
+}
+
+// closeSession 发送最后一条消息并关闭请求指定的会话
+func (p *Client) closeSession(msg outboundMessage) {
+	c := msg.close
+	if !c.sess.IsConnected() {
+		return
+	}
+	if err := c.sess.CloseWithMessage(msg.data, c.code, c.reason); err != nil {
+		log.Printf("🔴 Failed to close session of player %d: %v", p.GetID(), err)
+	}
+}
+
+// send 通过当前会话发送一条消息
+func (p *Client) send(msg outboundMessage) {
+	sess := p.GetSession()
+	if sess == nil {
 		log.Printf("🔴 Cannot write message: player or context is nil")
 		return
 	}
 
-	err := p.Session.SendDatagram(data)
+	var err error
+	if msg.reliable {
+		err = sess.SendReliable(msg.data)
+	} else {
+		err = sess.SendDatagram(msg.data)
+	}
 	if err != nil {
 		log.Printf("🔴 Failed to write message to player %d: %v", p.GetID(), err)
 	} else {
-		log.Printf("🟢 Message written to player %d, length: %d", p.GetID(), len(data))
+		log.Printf("🟢 Message written to player %d, length: %d", p.GetID(), len(msg.data))
 	}
 }
@@ -0,0 +1,95 @@
+package replay
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// 回放文件格式，见 proto/replay.proto
+//
+//	"LSRP" | uint32 版本号 | [uint32 长度 | ReplayHeader] | [uint32 长度 | ReplayRecord]...
+//
+// 所有整数均为大端序
+const (
+	// Magic 回放文件开头的魔数
+	Magic = "LSRP"
+	// FormatVersion 当前写入的文件格式版本
+	FormatVersion uint32 = 1
+	// FileExt 回放文件扩展名
+	FileExt = ".lsrp"
+
+	// MaxRecordSize 单条记录的最大长度，防止读取损坏的文件时分配过大的内存
+	MaxRecordSize = 64 << 20
+)
+
+var (
+	// ErrBadMagic 文件不是回放文件
+	ErrBadMagic = errors.New("replay: bad magic")
+	// ErrUnsupportedVersion 文件格式版本不受支持
+	ErrUnsupportedVersion = errors.New("replay: unsupported format version")
+)
+
+// writePreamble 写入魔数与格式版本
+func writePreamble(w io.Writer) error {
+	var buf [8]byte
+	copy(buf[:4], Magic)
+	binary.BigEndian.PutUint32(buf[4:], FormatVersion)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+// readPreamble 读取并校验魔数与格式版本
+func readPreamble(r io.Reader) (uint32, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	if string(buf[:4]) != Magic {
+		return 0, ErrBadMagic
+	}
+	version := binary.BigEndian.Uint32(buf[4:])
+	if version == 0 || version > FormatVersion {
+		return version, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	return version, nil
+}
+
+// writeMessage 写入一条带长度前缀的 protobuf 消息
+func writeMessage(w io.Writer, msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var prefix [4]byte
+	binary.BigEndian.PutUint32(prefix[:], uint32(len(data)))
+	if _, err := w.Write(prefix[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// readMessage 读取一条带长度前缀的 protobuf 消息
+// 在记录边界处结束时返回 io.EOF，记录被截断时返回 io.ErrUnexpectedEOF
+func readMessage(r io.Reader, msg proto.Message) error {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return err
+	}
+	size := binary.BigEndian.Uint32(prefix[:])
+	if size > MaxRecordSize {
+		return fmt.Errorf("replay: record size %d exceeds limit", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return proto.Unmarshal(data, msg)
+}
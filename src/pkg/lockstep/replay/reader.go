@@ -0,0 +1,90 @@
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"lockstep-core/src/messages"
+	"os"
+)
+
+// ReplayReader 按写入顺序读取回放文件中的记录
+type ReplayReader struct {
+	reader  *bufio.Reader
+	closer  io.Closer
+	version uint32
+	header  *messages.ReplayHeader
+}
+
+// OpenReplay 打开回放文件并读取文件头
+func OpenReplay(path string) (*ReplayReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	rr, err := NewReplayReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	rr.closer = file
+	return rr, nil
+}
+
+// NewReplayReader 从 r 中读取回放，r 需要位于回放文件开头
+func NewReplayReader(r io.Reader) (*ReplayReader, error) {
+	br := bufio.NewReader(r)
+	version, err := readPreamble(br)
+	if err != nil {
+		return nil, err
+	}
+	header := &messages.ReplayHeader{}
+	if err := readMessage(br, header); err != nil {
+		return nil, fmt.Errorf("replay: read header: %w", err)
+	}
+	return &ReplayReader{
+		reader:  br,
+		version: version,
+		header:  header,
+	}, nil
+}
+
+// Version 文件格式版本
+func (rr *ReplayReader) Version() uint32 {
+	return rr.version
+}
+
+// Header 回放文件头
+func (rr *ReplayReader) Header() *messages.ReplayHeader {
+	return rr.header
+}
+
+// Next 读取下一条记录，读完时返回 io.EOF
+func (rr *ReplayReader) Next() (*messages.ReplayRecord, error) {
+	record := &messages.ReplayRecord{}
+	if err := readMessage(rr.reader, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// NextFrame 跳过阶段变更与快照，读取下一帧，读完时返回 io.EOF
+func (rr *ReplayReader) NextFrame() (*messages.FrameData, error) {
+	for {
+		record, err := rr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if frame := record.GetFrame(); frame != nil {
+			return frame, nil
+		}
+	}
+}
+
+// Close 关闭由 OpenReplay 打开的文件
+func (rr *ReplayReader) Close() error {
+	if rr.closer == nil {
+		return nil
+	}
+	return rr.closer.Close()
+}
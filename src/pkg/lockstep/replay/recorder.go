@@ -0,0 +1,180 @@
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder 将一场对局的帧、阶段变更与快照写入回放文件
+// 实现了 lockstep_sync.FrameSink，可直接挂载到 ServerSyncData
+type Recorder struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	// 第一次写入失败的错误，此后的记录都会被丢弃
+	err error
+	// 已写入的帧数量
+	frames uint32
+}
+
+// NewRecorder 在 dir 下创建回放文件并写入文件头
+// 文件名为 room-{roomID}-{开始时间}.lsrp
+func NewRecorder(dir string, header *messages.ReplayHeader) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("replay: create dir: %w", err)
+	}
+	if header.StartedAtUnixMs == 0 {
+		header.StartedAtUnixMs = time.Now().UnixMilli()
+	}
+	header.Version = FormatVersion
+
+	name := fmt.Sprintf("room-%d-%d%s", header.RoomId, header.StartedAtUnixMs, FileExt)
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: create file: %w", err)
+	}
+
+	r := &Recorder{
+		path:   path,
+		file:   file,
+		writer: bufio.NewWriter(file),
+	}
+	if err := writePreamble(r.writer); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("replay: write preamble: %w", err)
+	}
+	if err := writeMessage(r.writer, header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("replay: write header: %w", err)
+	}
+	log.Printf("📼 Recording room %d to %s", header.RoomId, path)
+	return r, nil
+}
+
+// Path 回放文件路径
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// OnStoreFrame 记录一帧权威帧数据
+func (r *Recorder) OnStoreFrame(frameID uint32, frameData *world.FrameData) {
+	r.write(&messages.ReplayRecord{
+		Payload: &messages.ReplayRecord_Frame{Frame: frameData},
+	})
+	r.mu.Lock()
+	r.frames++
+	r.mu.Unlock()
+}
+
+// OnStoreSnapshot 记录一个状态快照
+func (r *Recorder) OnStoreSnapshot(frameID uint32, snapshot world.Snapshot) {
+	r.write(&messages.ReplayRecord{
+		Payload: &messages.ReplayRecord_Snapshot{
+			Snapshot: &messages.ReplaySnapshot{FrameId: frameID, Data: snapshot},
+		},
+	})
+}
+
+// RecordStageChange 记录房间阶段变更
+// frameID 为变更发生时最后一个已步进的帧号
+func (r *Recorder) RecordStageChange(frameID uint32, stage constants.Stage, data []byte) {
+	r.write(&messages.ReplayRecord{
+		Payload: &messages.ReplayRecord_StageChange{
+			StageChange: &messages.ReplayStageChange{
+				FrameId:  frameID,
+				NewStage: uint32(stage),
+				Data:     data,
+			},
+		},
+	})
+}
+
+// write 写入一条记录，写入失败后不再继续写入
+func (r *Recorder) write(record *messages.ReplayRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil || r.file == nil {
+		return
+	}
+	if err := writeMessage(r.writer, record); err != nil {
+		r.err = err
+		log.Printf("🔴 Replay %s write error, recording stopped: %v", r.path, err)
+	}
+}
+
+// Flush 将缓冲中的记录写入文件，使文件内容截止到最后一条完整的记录
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil || r.err != nil {
+		return r.err
+	}
+	if err := r.writer.Flush(); err != nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// CopyReplay 将回放文件 src 复制到 dir 下，返回新文件的路径
+// 文件名为 src 的文件名加上复制时间，src 可以是仍在录制中且已 Flush 的文件
+func CopyReplay(src string, dir string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	if _, err := readPreamble(in); err != nil {
+		return "", err
+	}
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("replay: create dir: %w", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(src), FileExt)
+	path := filepath.Join(dir, fmt.Sprintf("%s-dump-%d%s", base, time.Now().UnixMilli(), FileExt))
+	out, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("replay: create file: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return path, err
+	}
+	return path, out.Close()
+}
+
+// Close 刷新缓冲并关闭回放文件，可重复调用
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return r.err
+	}
+	if err := r.writer.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	r.file = nil
+	log.Printf("📼 Replay %s closed (%d frames)", r.path, r.frames)
+	return r.err
+}
@@ -0,0 +1,111 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/world"
+	"os"
+	"testing"
+)
+
+// recordTestMatch 录制一场包含 3 帧、1 个快照与 1 次阶段变更的对局，返回回放文件路径
+func recordTestMatch(t *testing.T) string {
+	t.Helper()
+	header := &messages.ReplayHeader{RoomId: 7, RoomName: "test", PlayerIds: []uint32{1, 2}, FrameIntervalMs: 66}
+	r, err := NewRecorder(t.TempDir(), header)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	r.RecordStageChange(0, constants.STAGE_InGame, []byte("start"))
+	r.OnStoreFrame(1, &world.FrameData{FrameId: 1})
+	r.OnStoreFrame(2, &world.FrameData{FrameId: 2})
+	r.OnStoreSnapshot(2, []byte("state@2"))
+	r.OnStoreFrame(3, &world.FrameData{FrameId: 3})
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return r.Path()
+}
+
+func TestRecorderReaderRoundTrip(t *testing.T) {
+	rr, err := OpenReplay(recordTestMatch(t))
+	if err != nil {
+		t.Fatalf("OpenReplay: %v", err)
+	}
+	defer rr.Close()
+
+	if rr.Version() != FormatVersion {
+		t.Fatalf("Version = %d, want %d", rr.Version(), FormatVersion)
+	}
+	header := rr.Header()
+	if header.GetRoomId() != 7 || header.GetRoomName() != "test" || len(header.GetPlayerIds()) != 2 ||
+		header.GetFrameIntervalMs() != 66 || header.GetStartedAtUnixMs() == 0 {
+		t.Fatalf("unexpected header: %v", header)
+	}
+
+	record, err := rr.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	stage := record.GetStageChange()
+	if stage == nil || stage.GetNewStage() != uint32(constants.STAGE_InGame) || string(stage.GetData()) != "start" {
+		t.Fatalf("first record = %v, want the stage change", record)
+	}
+
+	// NextFrame 跳过快照
+	for _, want := range []uint32{1, 2, 3} {
+		frame, err := rr.NextFrame()
+		if err != nil || frame.GetFrameId() != want {
+			t.Fatalf("NextFrame = %v, %v, want frame %d", frame, err, want)
+		}
+	}
+	if _, err := rr.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Next after the last record = %v, want io.EOF", err)
+	}
+}
+
+func TestLoadReplay(t *testing.T) {
+	r, err := LoadReplay(recordTestMatch(t))
+	if err != nil {
+		t.Fatalf("LoadReplay: %v", err)
+	}
+	if len(r.Frames) != 3 || r.LastFrameID() != 3 {
+		t.Fatalf("loaded %d frames up to %d, want 3", len(r.Frames), r.LastFrameID())
+	}
+	if len(r.Snapshots) != 1 || string(r.Snapshots[0].GetData()) != "state@2" {
+		t.Fatalf("snapshots = %v", r.Snapshots)
+	}
+}
+
+func TestReaderRejectsBadFiles(t *testing.T) {
+	if _, err := NewReplayReader(bytes.NewReader([]byte("NOPE\x00\x00\x00\x01"))); !errors.Is(err, ErrBadMagic) {
+		t.Errorf("bad magic: error = %v, want ErrBadMagic", err)
+	}
+
+	future := []byte(Magic + "\x00\x00\x00\x00")
+	binary.BigEndian.PutUint32(future[4:], FormatVersion+1)
+	if _, err := NewReplayReader(bytes.NewReader(future)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("future version: error = %v, want ErrUnsupportedVersion", err)
+	}
+
+	data, err := os.ReadFile(recordTestMatch(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, err := NewReplayReader(bytes.NewReader(data[:len(data)-1]))
+	if err != nil {
+		t.Fatalf("NewReplayReader: %v", err)
+	}
+	for {
+		if _, err = rr.Next(); err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated file: error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
@@ -0,0 +1,113 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/world"
+	"sort"
+)
+
+// Replay 完整加载到内存中的回放，用于回放房间播放
+type Replay struct {
+	Header *messages.ReplayHeader
+	// 默认 chunk 的帧，帧号升序
+	Frames []*world.FrameData
+	// 帧号升序
+	Snapshots []*messages.ReplaySnapshot
+}
+
+// LoadReplay 读取整个回放文件
+func LoadReplay(path string) (*Replay, error) {
+	rr, err := OpenReplay(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rr.Close()
+	return ReadAll(rr)
+}
+
+// ReadAll 读取 rr 中剩余的默认 chunk 的帧与快照
+// 阶段变更记录与其他 chunk 的帧会被忽略
+func ReadAll(rr *ReplayReader) (*Replay, error) {
+	r := &Replay{Header: rr.Header()}
+	for {
+		record, err := rr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("replay: read record: %w", err)
+		}
+		if frame := record.GetFrame(); frame != nil && frame.GetChunkId() == 0 {
+			r.Frames = append(r.Frames, frame)
+		}
+		if snapshot := record.GetSnapshot(); snapshot != nil {
+			r.Snapshots = append(r.Snapshots, snapshot)
+		}
+	}
+	if len(r.Frames) == 0 {
+		return nil, fmt.Errorf("replay: no frames")
+	}
+	sort.Slice(r.Frames, func(i, j int) bool {
+		return r.Frames[i].GetFrameId() < r.Frames[j].GetFrameId()
+	})
+	sort.Slice(r.Snapshots, func(i, j int) bool {
+		return r.Snapshots[i].GetFrameId() < r.Snapshots[j].GetFrameId()
+	})
+	return r, nil
+}
+
+// LastFrameID 回放的最后一帧
+func (r *Replay) LastFrameID() uint32 {
+	if len(r.Frames) == 0 {
+		return 0
+	}
+	return r.Frames[len(r.Frames)-1].GetFrameId()
+}
+
+// FrameAt 获取步进到 frameID 所需的帧数据
+func (r *Replay) FrameAt(frameID uint32) (*world.FrameData, bool) {
+	i := sort.Search(len(r.Frames), func(i int) bool {
+		return r.Frames[i].GetFrameId() >= frameID
+	})
+	if i < len(r.Frames) && r.Frames[i].GetFrameId() == frameID {
+		return r.Frames[i], true
+	}
+	return nil, false
+}
+
+// Start 播放的起点，返回第一个要播放的帧号与播放前需要载入的快照
+// 回放包含第 1 帧时从第 1 帧开始；否则从最早的、其后一帧仍在回放中的快照开始，
+// 客户端需先载入该快照；两者都没有时从回放中最早的帧开始
+func (r *Replay) Start() (uint32, *messages.ReplaySnapshot) {
+	if len(r.Frames) == 0 {
+		return 1, nil
+	}
+	first := r.Frames[0].GetFrameId()
+	if first <= 1 {
+		return 1, nil
+	}
+	for _, snapshot := range r.Snapshots {
+		next := snapshot.GetFrameId() + 1
+		if next < first {
+			continue
+		}
+		if _, ok := r.FrameAt(next); ok || next > r.LastFrameID() {
+			return next, snapshot
+		}
+	}
+	return first, nil
+}
+
+// SnapshotAt 获取 frameID 帧的快照
+func (r *Replay) SnapshotAt(frameID uint32) (world.Snapshot, bool) {
+	i := sort.Search(len(r.Snapshots), func(i int) bool {
+		return r.Snapshots[i].GetFrameId() >= frameID
+	})
+	if i < len(r.Snapshots) && r.Snapshots[i].GetFrameId() == frameID {
+		return r.Snapshots[i].GetData(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,37 @@
+package replay
+
+import (
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/world"
+	"testing"
+)
+
+func TestStartFromFirstFrame(t *testing.T) {
+	r := &Replay{Frames: []*world.FrameData{{FrameId: 1}, {FrameId: 2}}}
+	if start, snapshot := r.Start(); start != 1 || snapshot != nil {
+		t.Fatalf("Start() = %d, %v, want 1 without snapshot", start, snapshot)
+	}
+}
+
+func TestStartFromSnapshot(t *testing.T) {
+	r := &Replay{
+		Frames: []*world.FrameData{{FrameId: 40}, {FrameId: 41}, {FrameId: 42}, {FrameId: 43}},
+		Snapshots: []*messages.ReplaySnapshot{
+			// 之后的帧不在回放中，不能作为起点
+			{FrameId: 30},
+			{FrameId: 41},
+			{FrameId: 42},
+		},
+	}
+	start, snapshot := r.Start()
+	if start != 42 || snapshot.GetFrameId() != 41 {
+		t.Fatalf("Start() = %d, snapshot %d, want 42 after snapshot 41", start, snapshot.GetFrameId())
+	}
+}
+
+func TestStartWithoutSnapshot(t *testing.T) {
+	r := &Replay{Frames: []*world.FrameData{{FrameId: 7}, {FrameId: 8}}}
+	if start, snapshot := r.Start(); start != 7 || snapshot != nil {
+		t.Fatalf("Start() = %d, %v, want 7 without snapshot", start, snapshot)
+	}
+}
@@ -0,0 +1,102 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// SnapshotIntervalFrames 每隔多少帧生成一次快照，0 为不生成
+func (room *Room) SnapshotIntervalFrames() uint32 {
+	if room.LockstepConfig.SnapshotInterval == nil {
+		return config.DefaultSnapshotInterval
+	}
+	return *room.LockstepConfig.SnapshotInterval
+}
+
+// takeSnapshot 在 frameID 帧步进完成后按配置的间隔向游戏世界请求快照
+func (room *Room) takeSnapshot(frameID uint32) {
+	interval := room.SnapshotIntervalFrames()
+	if interval == 0 || frameID%interval != 0 {
+		return
+	}
+	snapshot := room.Game.GetSnapshot(frameID, world.WorldOptions{ChunkID: 0})
+	if len(snapshot) == 0 {
+		return
+	}
+	room.SyncData.StoreSnapshot(frameID, snapshot)
+}
+
+// needsSnapshotCatchUp 判断客户端是否需要通过快照追帧
+// 当客户端 ack 之后的帧已被淘汰，或落后超过一个快照间隔且存在更新的快照时需要
+// nextFrame 为客户端可见的最新帧，只会使用不晚于它的快照
+func (room *Room) needsSnapshotCatchUp(ack, nextFrame uint32) (uint32, world.Snapshot, bool) {
+	snapshotFrame, snapshot, ok := room.SyncData.Snapshots.LatestAtOrBefore(nextFrame)
+	if !ok || snapshotFrame <= ack {
+		return 0, nil, false
+	}
+	if _, ok := room.SyncData.GetFrame(ack + 1); !ok {
+		return snapshotFrame, snapshot, true
+	}
+	if nextFrame-ack > room.SnapshotIntervalFrames() {
+		return snapshotFrame, snapshot, true
+	}
+	return 0, nil, false
+}
+
+// trySnapshotCatchUp 若客户端严重落后，则发送最新快照与其后的所有帧
+// 同一快照在客户端确认前，至少间隔一个快照间隔才会重发
+// 返回 true 表示本帧已通过快照处理该客户端
+func (room *Room) trySnapshotCatchUp(c *client.Client, ack, nextFrame uint32) bool {
+	snapshotFrame, snapshot, ok := room.needsSnapshotCatchUp(ack, nextFrame)
+	if !ok {
+		return false
+	}
+	if c.SentSnapshotFrameID.Load() == snapshotFrame &&
+		nextFrame-c.SnapshotSentAtFrame.Load() <= room.SnapshotIntervalFrames() {
+		// 已发送过该快照，等待客户端确认
+		return true
+	}
+
+	// 快照之后的帧最多携带一个数据报的帧数量，其余帧在客户端确认快照后正常发送
+	frames := room.collectClientFrames(c, snapshotFrame+1, nextFrame)
+	frames = frames[:min(len(frames), room.FramesPerDatagram())]
+	resp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_Snapshot{
+			Snapshot: &messages.ResponseSnapshot{
+				FrameId: snapshotFrame,
+				Data:    snapshot,
+				Frames:  frames,
+			},
+		},
+	}
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		log.Printf("Failed to marshal snapshot for client %d: %v", c.GetID(), err)
+		return true
+	}
+	c.SentSnapshotFrameID.Store(snapshotFrame)
+	c.SnapshotSentAtFrame.Store(nextFrame)
+	c.Write(data)
+	log.Printf("📸 Sent snapshot of frame %d to player %d in room %d (ack: %d, next: %d)",
+		snapshotFrame, c.GetID(), room.ID, ack, nextFrame)
+	return true
+}
+
+// collectFrames 获取 [from, to] 区间内仍保存的帧
+func (room *Room) collectFrames(from, to uint32) []*messages.FrameData {
+	if to < from {
+		return []*messages.FrameData{}
+	}
+	frames := make([]*messages.FrameData, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		if frame, ok := room.SyncData.GetFrame(i); ok {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
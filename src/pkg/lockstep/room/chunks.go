@@ -0,0 +1,167 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	lockstep_sync "lockstep-core/src/pkg/lockstep/sync"
+	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+	"maps"
+	"slices"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ChunkSubscriptionLimit 每个客户端最多同时订阅的 chunk 数量
+func (room *Room) ChunkSubscriptionLimit() int {
+	if room.LockstepConfig.MaxChunksPerClient == nil {
+		return config.DefaultMaxChunksPerClient
+	}
+	return int(*room.LockstepConfig.MaxChunksPerClient)
+}
+
+// activeChunks 至少被一名玩家或观战者订阅的 chunk，升序，总是包含默认 chunk
+func (room *Room) activeChunks() []int {
+	set := map[int]struct{}{lockstep_sync.DefaultChunkID: {}}
+	collect := func(key uint32, value *client.Client) bool {
+		for _, chunkID := range value.Chunks.IDs() {
+			set[chunkID] = struct{}{}
+		}
+		return true
+	}
+	room.ClientsContainer.Clients.Range(collect)
+	room.Spectators.Clients.Range(collect)
+	return slices.Sorted(maps.Keys(set))
+}
+
+// produceChunkFrames 获取并保存默认 chunk 以外被订阅的 chunk 在 frameID 帧的帧数据
+// 在默认 chunk 的 GetFrameData 之后调用
+func (room *Room) produceChunkFrames(frameID, oldestAck uint32) {
+	for _, chunkID := range room.activeChunks() {
+		if chunkID == lockstep_sync.DefaultChunkID {
+			continue
+		}
+		frameData := room.Game.GetFrameData(frameID, world.WorldOptions{ChunkID: chunkID})
+		frameData.FrameId = frameID
+		frameData.OldestAckFrameId = oldestAck
+		frameData.ChunkId = uint32(chunkID)
+		room.SyncData.StoreChunkFrame(chunkID, frameID, &frameData)
+	}
+}
+
+// collectChunkFrames 获取 chunks 在 [from, to] 区间内仍保存的帧，各 chunk 的帧号升序
+func (room *Room) collectChunkFrames(chunks []int, from, to uint32) map[int][]*messages.FrameData {
+	result := make(map[int][]*messages.FrameData, len(chunks))
+	if to < from {
+		return result
+	}
+	for _, chunkID := range chunks {
+		frames := make([]*messages.FrameData, 0, to-from+1)
+		for i := from; i <= to; i++ {
+			if frame, ok := room.SyncData.GetChunkFrame(chunkID, i); ok {
+				frames = append(frames, frame)
+			}
+		}
+		result[chunkID] = frames
+	}
+	return result
+}
+
+// framesFor 从按 chunk 预组装的帧中挑出客户端所订阅的、ack 之后的帧，帧号升序
+// 客户端订阅某 chunk 之前的帧不会发送给它
+func framesFor(c *client.Client, ack uint32, chunkFrames map[int][]*messages.FrameData) []*messages.FrameData {
+	frames := make([]*messages.FrameData, 0)
+	chunks := c.Chunks.IDs()
+	for _, chunkID := range chunks {
+		since, ok := c.Chunks.Since(chunkID)
+		if !ok {
+			continue
+		}
+		all := chunkFrames[chunkID]
+		start := sort.Search(len(all), func(i int) bool {
+			return all[i].GetFrameId() > ack && all[i].GetFrameId() >= since
+		})
+		frames = append(frames, all[start:]...)
+	}
+	if len(chunks) > 1 {
+		sort.SliceStable(frames, func(i, j int) bool {
+			return frames[i].GetFrameId() < frames[j].GetFrameId()
+		})
+	}
+	return frames
+}
+
+// collectClientFrames 获取客户端所订阅的 chunk 在 [from, to] 区间内仍保存的帧
+func (room *Room) collectClientFrames(c *client.Client, from, to uint32) []*messages.FrameData {
+	if to < from {
+		return []*messages.FrameData{}
+	}
+	return framesFor(c, from-1, room.collectChunkFrames(c.Chunks.IDs(), from, to))
+}
+
+// handleSubscribeChunks 处理订阅 chunk 请求，玩家与观战者均可订阅
+// 新订阅的 chunk 从下一帧开始发送
+func (room *Room) handleSubscribeChunks(from *client.Client, payload *messages.SessionRequest_SubscribeChunks) {
+	if room == nil || from == nil || payload == nil || payload.SubscribeChunks == nil {
+		return
+	}
+	if room.Game == nil {
+		return
+	}
+	since := room.SyncData.NextFrameID.Load()
+	rejected := make([]uint32, 0)
+	for _, id := range payload.SubscribeChunks.GetChunkIds() {
+		chunkID := int(id)
+		if _, ok := from.Chunks.Since(chunkID); ok {
+			continue
+		}
+		if from.Chunks.Len() >= room.ChunkSubscriptionLimit() || !room.Game.OnChunkSubscribe(from.GetID(), chunkID) {
+			rejected = append(rejected, id)
+			continue
+		}
+		from.Chunks.Subscribe(chunkID, since)
+	}
+	if len(rejected) > 0 {
+		log.Printf("⚠️ Room %d rejected chunk subscriptions %v of client %d", room.ID, rejected, from.GetID())
+	}
+	room.sendChunkSubscriptions(from, rejected, since)
+}
+
+// handleUnsubscribeChunks 处理取消订阅 chunk 请求
+func (room *Room) handleUnsubscribeChunks(from *client.Client, payload *messages.SessionRequest_UnsubscribeChunks) {
+	if room == nil || from == nil || payload == nil || payload.UnsubscribeChunks == nil {
+		return
+	}
+	if room.Game == nil {
+		return
+	}
+	for _, id := range payload.UnsubscribeChunks.GetChunkIds() {
+		if from.Chunks.Unsubscribe(int(id)) {
+			room.Game.OnChunkUnsubscribe(from.GetID(), int(id))
+		}
+	}
+	room.sendChunkSubscriptions(from, []uint32{}, room.SyncData.NextFrameID.Load())
+}
+
+// sendChunkSubscriptions 告知客户端其当前订阅的全部 chunk
+func (room *Room) sendChunkSubscriptions(c *client.Client, rejected []uint32, since uint32) {
+	chunks := c.Chunks.IDs()
+	chunkIDs := make([]uint32, 0, len(chunks))
+	for _, chunkID := range chunks {
+		chunkIDs = append(chunkIDs, uint32(chunkID))
+	}
+	resp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_ChunkSubscriptions{
+			ChunkSubscriptions: &messages.ResponseChunkSubscriptions{
+				ChunkIds:         chunkIDs,
+				RejectedChunkIds: rejected,
+				SinceFrameId:     since,
+			},
+		},
+	}
+	if b, err := proto.Marshal(resp); err == nil {
+		c.WriteReliable(b)
+	}
+}
@@ -15,6 +15,8 @@ import (
 type ClientsContainer struct {
 	Clients client.PlayerMap // 玩家映射
 	*utils.SafeIDAllocator
+	// 分配的用户 ID 的起始值，用于区分玩家与观战者的 ID 空间
+	idBase uint32
 }
 
 // NewClientsContainer 创建一个新的 RoomContext 实例
@@ -25,7 +27,16 @@ func NewClientsContainer(cfg config.LockstepConfig) *ClientsContainer {
 }
 
 func (rc *ClientsContainer) GetNextUserID() (uint32, error) {
-	return rc.SafeIDAllocator.Allocate()
+	id, err := rc.SafeIDAllocator.Allocate()
+	if err != nil {
+		return id, err
+	}
+	return rc.idBase + id, nil
+}
+
+// FreeUserID 释放由 GetNextUserID 分配的用户 ID
+func (rc *ClientsContainer) FreeUserID(uid uint32) {
+	rc.SafeIDAllocator.Free(uid - rc.idBase)
 }
 
 func (rc *ClientsContainer) HasUser(uid uint32) bool {
@@ -57,14 +68,23 @@ func (rc *ClientsContainer) AddUser(p *client.Client) {
 // 这里是最终处理逻辑，不要直接调用
 func (rc *ClientsContainer) DelUser(uid uint32) {
 	rc.Clients.Delete(uid)
-	rc.SafeIDAllocator.Free(uid)
+	rc.FreeUserID(uid)
 }
 
-// CloseAll 关闭所有用户连接
-func (rc *ClientsContainer) CloseAll() {
+// CloseAll 向所有在线用户尽力发送最后一条消息后关闭其连接
+func (rc *ClientsContainer) CloseAll(data []byte, code uint32, reason string) {
 	rc.Clients.Range(func(key uint32, player *client.Client) bool {
-		if player != nil && player.Session != nil {
-			player.Session.Close()
+		if player == nil {
+			return true
+		}
+		sess := player.GetSession()
+		if sess == nil {
+			return true
+		}
+		if sess.IsConnected() && data != nil {
+			player.CloseWithMessage(data, code, reason)
+		} else {
+			player.Close()
 		}
 		return true
 	})
@@ -79,7 +99,10 @@ func (rc *ClientsContainer) GetPlayerCount() int {
 func (rc *ClientsContainer) GetActivePlayerCount() int {
 	count := 0
 	rc.Clients.Range(func(key uint32, player *client.Client) bool {
-		if player != nil && player.Session != nil && player.Session.IsConnected() {
+		if player == nil {
+			return true
+		}
+		if sess := player.GetSession(); sess != nil && sess.IsConnected() {
 			count++
 		}
 		return true
@@ -124,7 +147,7 @@ func (rc *ClientsContainer) HasAllPlayerLoaded() bool {
 	return allLoaded
 }
 
-// BroadcastMessage 广播 protobuf 消息
+// BroadcastMessage 通过可靠通道广播 protobuf 控制消息
 func (rc *ClientsContainer) BroadcastMessage(msg protoreflect.ProtoMessage, excludeIDs []uint32) {
 	data, err := proto.Marshal(msg)
 	if err != nil {
@@ -148,21 +171,28 @@ func (rc *ClientsContainer) BroadcastMessage(msg protoreflect.ProtoMessage, excl
 			return true
 		}
 
-		client.Write(data)
+		client.WriteReliable(data)
 		return true
 	})
 }
 
-// SendMessageToUser 单播消息给指定用户
+// SendMessageToUser 通过可靠通道单播控制消息给指定用户
 func (rc *ClientsContainer) SendMessageToUser(data []byte, userID uint32) {
 	if client, ok := rc.Clients.Load(userID); ok {
 		rc.SendMessageToUserByPlayer(data, client)
 	}
 }
 
-// SendMessageToUserByPlayer 通过 Player 实例发送消息
+// SendMessageToUserByPlayer 通过 Player 实例以可靠通道发送控制消息
 func (rc *ClientsContainer) SendMessageToUserByPlayer(data []byte, client *client.Client) {
 	if client != nil && client.Session.IsConnected() {
+		client.WriteReliable(data)
+	}
+}
+
+// SendDatagramToUser 以数据报单播消息给指定用户
+func (rc *ClientsContainer) SendDatagramToUser(data []byte, userID uint32) {
+	if client, ok := rc.Clients.Load(userID); ok && client.Session.IsConnected() {
 		client.Write(data)
 	}
 }
@@ -0,0 +1,108 @@
+package room
+
+import (
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"log"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// closeRequest 外部请求关闭房间时携带的原因
+type closeRequest struct {
+	code   messages.CloseReason
+	reason string
+	// 仅用于关闭预告：距离强制关闭的时间
+	remaining time.Duration
+}
+
+// broadcastClosePending 向所有玩家与观战者预告房间即将被关闭
+func (room *Room) broadcastClosePending(notice closeRequest) {
+	resp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_ClosePending{
+			ClosePending: &messages.ResponseClosePending{
+				Code:        notice.code,
+				Reason:      notice.reason,
+				RemainingMs: uint32(notice.remaining.Milliseconds()),
+			},
+		},
+	}
+	room.broadcastWithSpectators(resp, []uint32{})
+	log.Printf("🛑 Room %d will be closed in %v (%s): %s", room.ID, notice.remaining, notice.code, notice.reason)
+}
+
+// roomClosedMessage 制作房间关闭前发送给客户端的最后一条消息
+func roomClosedMessage(code messages.CloseReason, reason string) []byte {
+	sresp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_RoomClosed{
+			RoomClosed: &messages.ResponseRoomClosed{Code: code, Reason: reason},
+		},
+	}
+	b, err := proto.Marshal(sresp)
+	if err != nil {
+		log.Printf("🔴 Failed to marshal ResponseRoomClosed: %v", err)
+		return nil
+	}
+	return b
+}
+
+// kickedMessage 制作玩家被移出房间前发送给该玩家的最后一条消息
+func kickedMessage(code messages.CloseReason, reason string) []byte {
+	sresp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_Kicked{
+			Kicked: &messages.ResponseKicked{Code: code, Reason: reason},
+		},
+	}
+	b, err := proto.Marshal(sresp)
+	if err != nil {
+		log.Printf("🔴 Failed to marshal ResponseKicked: %v", err)
+		return nil
+	}
+	return b
+}
+
+// kickPlayer 告知玩家被踢出的原因后将其彻底移出房间，不进入断线等待重连状态
+// 仅在房间主循环中调用
+func (room *Room) kickPlayer(uid uint32, code messages.CloseReason, reason string) {
+	if IsSpectatorID(uid) {
+		room.kickSpectator(uid, code, reason)
+		return
+	}
+	player, ok := room.ClientsContainer.Clients.Load(uid)
+	if !ok || player == nil {
+		return
+	}
+	log.Printf("🟠 Kicking player %d from room %d (%s): %s", uid, room.ID, code, reason)
+
+	if sess := player.GetSession(); sess != nil && sess.IsConnected() {
+		player.CloseWithMessage(kickedMessage(code, reason), uint32(code), reason)
+	}
+	room.removePlayer(player)
+}
+
+// kickSpectator 告知观战者被踢出的原因后将其移出房间
+func (room *Room) kickSpectator(uid uint32, code messages.CloseReason, reason string) {
+	spectator, ok := room.Spectators.Clients.Load(uid)
+	if !ok || spectator == nil {
+		return
+	}
+	log.Printf("🟠 Kicking spectator %d from room %d (%s): %s", uid, room.ID, code, reason)
+
+	if sess := spectator.GetSession(); sess != nil && sess.IsConnected() {
+		spectator.CloseWithMessage(kickedMessage(code, reason), uint32(code), reason)
+	}
+	room.removeSpectator(spectator)
+}
+
+// takeOverSession 同一玩家在别处重新登录时，用新会话顶替仍在线的旧会话
+// 旧会话会收到 CLOSE_REASON_DUPLICATE_LOGIN 后被关闭，关闭经由出站队列，不与写入 goroutine 并发访问旧会话
+func (room *Room) takeOverSession(existing *client.Client, reconnecting *client.Client) {
+	old := existing.GetSession()
+	existing.SwapSession(reconnecting.Session)
+	if old != nil && old.IsConnected() {
+		code := messages.CloseReason_CLOSE_REASON_DUPLICATE_LOGIN
+		existing.RetireSession(old, kickedMessage(code, "logged in from another session"), uint32(code), "duplicate login")
+	}
+	log.Printf("🟠 Player %d logged in again to room %d, previous session replaced", existing.GetID(), room.ID)
+}
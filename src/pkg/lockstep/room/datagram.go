@@ -0,0 +1,114 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"log"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// maxDatagramsPerTick 每次帧时钟向单个客户端最多发送的帧数据报数量
+// 落后更多的客户端在之后的帧时钟中继续补发，或通过快照追帧
+const maxDatagramsPerTick = 4
+
+// FramesPerDatagram 单个数据报最多携带的帧数量
+func (room *Room) FramesPerDatagram() int {
+	if room.LockstepConfig.MaxFramesPerDatagram == nil || *room.LockstepConfig.MaxFramesPerDatagram == 0 {
+		return config.DefaultMaxFramesPerDatagram
+	}
+	return int(*room.LockstepConfig.MaxFramesPerDatagram)
+}
+
+// DatagramBytes 单个数据报帧数据的最大字节数
+func (room *Room) DatagramBytes() int {
+	if room.LockstepConfig.MaxDatagramBytes == nil || *room.LockstepConfig.MaxDatagramBytes == 0 {
+		return config.DefaultMaxDatagramBytes
+	}
+	return int(*room.LockstepConfig.MaxDatagramBytes)
+}
+
+// FrameRedundancyLimit 每一帧最多在连续多少次发送中重复携带
+func (room *Room) FrameRedundancyLimit() int {
+	if room.LockstepConfig.MaxFrameRedundancy == nil || *room.LockstepConfig.MaxFrameRedundancy == 0 {
+		return config.DefaultMaxFrameRedundancy
+	}
+	return int(*room.LockstepConfig.MaxFrameRedundancy)
+}
+
+// updateAck 记录客户端确认的帧并更新其链路估计
+// ack 只会向前推进，乱序到达的旧 ack 不会触发重发，也不计入 RTT 样本
+func (room *Room) updateAck(c *client.Client, ack uint32) {
+	if !c.AdvanceAck(ack) {
+		return
+	}
+	c.Link.OnAck(ack, time.Now())
+}
+
+// selectFrames 从客户端尚未确认的帧中挑出本次需要发送的帧，帧号升序
+// 最近的若干帧每次都会发送以抵御丢包，冗余的帧数由客户端的丢包率决定；
+// 更早的帧只在从未发送过，或上次发送后超过 RTO 仍未确认时重发
+func (room *Room) selectFrames(c *client.Client, frames []*messages.FrameData, newest uint32, now time.Time) []*messages.FrameData {
+	redundancy := uint32(c.Link.Redundancy(room.FrameRedundancyLimit()))
+	rto := c.Link.RTO()
+	limit := room.FramesPerDatagram() * maxDatagramsPerTick
+
+	selected := make([]*messages.FrameData, 0, min(len(frames), limit))
+	for _, frame := range frames {
+		if len(selected) >= limit {
+			break
+		}
+		frameID := frame.GetFrameId()
+		if newest-frameID < redundancy {
+			selected = append(selected, frame)
+			continue
+		}
+		if last, sent := c.Link.LastSent(frameID); !sent || now.Sub(last) >= rto {
+			selected = append(selected, frame)
+		}
+	}
+	return selected
+}
+
+// sendFrames 挑选需要发送的帧，并按帧数量与字节数上限拆分为多个数据报发送
+// 没有需要发送的帧时仍发送一个空帧包
+// newest 为客户端当前可见的最新帧
+func (room *Room) sendFrames(c *client.Client, frames []*messages.FrameData, newest uint32, lastInputFrame uint32) {
+	now := time.Now()
+	selected := room.selectFrames(c, frames, newest, now)
+	maxFrames, maxBytes := room.FramesPerDatagram(), room.DatagramBytes()
+
+	write := func(batch []*messages.FrameData) {
+		resp := &messages.SessionResponse{
+			Payload: &messages.SessionResponse_InGameFrames{
+				InGameFrames: &messages.ResponseInGameFrames{
+					Frames:           batch,
+					LastInputFrameId: lastInputFrame,
+				},
+			},
+		}
+		data, err := proto.Marshal(resp)
+		if err != nil {
+			log.Printf("Failed to marshal frame data for client %d: %v", c.GetID(), err)
+			return
+		}
+		c.Write(data)
+	}
+
+	batch := make([]*messages.FrameData, 0, min(len(selected), maxFrames))
+	batchBytes := 0
+	for _, frame := range selected {
+		size := proto.Size(frame)
+		if len(batch) > 0 && (len(batch) >= maxFrames || batchBytes+size > maxBytes) {
+			write(batch)
+			batch = make([]*messages.FrameData, 0, min(len(selected), maxFrames))
+			batchBytes = 0
+		}
+		batch = append(batch, frame)
+		batchBytes += size
+		c.Link.OnSend(frame.GetFrameId(), now)
+	}
+	write(batch)
+}
@@ -0,0 +1,142 @@
+package room
+
+import (
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// checksumWorld 游戏世界实现了 IChecksumWorld 时返回之
+func (room *Room) checksumWorld() (world.IChecksumWorld, bool) {
+	if room.Game == nil {
+		return nil, false
+	}
+	cw, ok := room.Game.(world.IChecksumWorld)
+	return cw, ok
+}
+
+// recordChecksum 保存步进到达 frameID 帧后的权威状态校验和
+func (room *Room) recordChecksum(frameID uint32) {
+	cw, ok := room.checksumWorld()
+	if !ok {
+		return
+	}
+	room.SyncData.Checksums.Store(frameID, cw.GetChecksum(frameID))
+}
+
+// resetDesync 新对局开始时清除上一场的不同步记录
+func (room *Room) resetDesync() {
+	room.firstDesyncFrame = 0
+	clear(room.pendingDesyncs)
+}
+
+// verifyChecksum 校验玩家上报的状态校验和
+// 不一致的玩家会被记录，在下一次帧时钟时合并通知游戏世界
+func (room *Room) verifyChecksum(from *client.Client, req *messages.RequestInGameFrames) {
+	if req.Checksum == nil {
+		return
+	}
+	if _, ok := room.checksumWorld(); !ok {
+		return
+	}
+	frameID := req.GetChecksumFrameId()
+	expected, ok := room.SyncData.Checksums.Get(frameID)
+	if !ok {
+		// 帧太旧或尚未步进，无法校验
+		return
+	}
+
+	desyncFrame := from.DesyncFrameID.Load()
+	if expected == req.GetChecksum() {
+		if desyncFrame != 0 && frameID > desyncFrame {
+			from.DesyncFrameID.Store(0)
+			log.Printf("✅ Player %d in room %d is back in sync at frame %d", from.GetID(), room.ID, frameID)
+		}
+		return
+	}
+	if desyncFrame != 0 {
+		// 已报告过，等待重新同步
+		return
+	}
+
+	from.DesyncFrameID.Store(frameID)
+	if room.pendingDesyncs == nil {
+		room.pendingDesyncs = make(map[uint32][]uint32)
+	}
+	room.pendingDesyncs[frameID] = append(room.pendingDesyncs[frameID], from.GetID())
+	log.Printf("⚠️ Player %d in room %d desynced at frame %d (checksum: %x, expected: %x)",
+		from.GetID(), room.ID, frameID, req.GetChecksum(), expected)
+}
+
+// flushDesyncs 按帧号顺序通知游戏世界本轮检测到的不同步，并向相应玩家发送 ResponseDesync
+func (room *Room) flushDesyncs() {
+	if len(room.pendingDesyncs) == 0 {
+		return
+	}
+	cw, ok := room.checksumWorld()
+	if !ok {
+		clear(room.pendingDesyncs)
+		return
+	}
+
+	frameIDs := make([]uint32, 0, len(room.pendingDesyncs))
+	for frameID := range room.pendingDesyncs {
+		frameIDs = append(frameIDs, frameID)
+	}
+	slices.Sort(frameIDs)
+
+	for _, frameID := range frameIDs {
+		uids := room.pendingDesyncs[frameID]
+		if room.firstDesyncFrame == 0 || frameID < room.firstDesyncFrame {
+			room.firstDesyncFrame = frameID
+			replayPath := "not recording"
+			if room.recorder != nil {
+				replayPath = room.recorder.Path()
+			}
+			log.Printf("🧨 Room %d first diverging frame: %d, players: %v, replay: %s",
+				room.ID, frameID, uids, replayPath)
+		}
+
+		desync := &messages.ResponseDesync{FrameId: frameID}
+		if cw.OnDesync(frameID, uids) {
+			desync.Resync = room.resyncSnapshot()
+		}
+		data, err := proto.Marshal(&messages.SessionResponse{
+			Payload: &messages.SessionResponse_Desync{Desync: desync},
+		})
+		if err != nil {
+			log.Printf("Failed to marshal desync for room %d: %v", room.ID, err)
+			continue
+		}
+		for _, uid := range uids {
+			room.ClientsContainer.SendMessageToUser(data, uid)
+		}
+	}
+	clear(room.pendingDesyncs)
+}
+
+// resyncSnapshot 制作用于重新同步的权威快照
+// 优先向游戏世界获取当前帧的快照，游戏世界不提供时使用最近保存的快照与其后的帧
+func (room *Room) resyncSnapshot() *messages.ResponseSnapshot {
+	current := room.SyncData.NextFrameID.Load() - 1
+	if snapshot := room.Game.GetSnapshot(current, world.WorldOptions{ChunkID: 0}); len(snapshot) > 0 {
+		return &messages.ResponseSnapshot{
+			FrameId: current,
+			Data:    snapshot,
+			Frames:  []*messages.FrameData{},
+		}
+	}
+	snapshotFrame, snapshot, ok := room.SyncData.Snapshots.LatestAtOrBefore(current)
+	if !ok {
+		return nil
+	}
+	return &messages.ResponseSnapshot{
+		FrameId: snapshotFrame,
+		Data:    snapshot,
+		Frames:  room.collectFrames(snapshotFrame+1, current),
+	}
+}
@@ -0,0 +1,94 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/pkg/lockstep/client"
+	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+	"time"
+)
+
+// IsDeterministic 是否启用悲观(确定性)锁步
+// LockstepConfig.DeterministicLockstep 大于 0 时启用
+func (room *Room) IsDeterministic() bool {
+	return room.LockstepConfig.DeterministicLockstep != nil && *room.LockstepConfig.DeterministicLockstep > 0
+}
+
+// DeterministicTimeout 悲观锁步下等待迟到输入的超时时间
+func (room *Room) DeterministicTimeout() time.Duration {
+	if room.LockstepConfig.DeterministicTimeout == nil {
+		return config.DefaultDeterministicTimeout * time.Millisecond
+	}
+	return time.Duration(*room.LockstepConfig.DeterministicTimeout) * time.Millisecond
+}
+
+// AcceptDeterministicInput 悲观锁步下判断某帧的输入是否可以被接受
+// 已经步进过的帧不再接受输入（已用空输入代替），
+// 超前于 NextFrameID 超过 DeterministicLockstep 帧的输入也不被接受
+func (room *Room) AcceptDeterministicInput(frameID uint32) bool {
+	nextFrame := room.SyncData.NextFrameID.Load()
+	if frameID < nextFrame {
+		return false
+	}
+	return frameID-nextFrame < uint32(*room.LockstepConfig.DeterministicLockstep)
+}
+
+// pendingInputPlayers 获取尚未提交 frameID 帧输入的在线玩家
+// 加载超时后未加载完毕就开始游戏的玩家在加载完毕前不参与等待
+func (room *Room) pendingInputPlayers(frameID uint32) []uint32 {
+	pending := make([]uint32, 0)
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value == nil || value.Session == nil || !value.Session.IsConnected() || !value.IsLoaded {
+			return true
+		}
+		if !value.SubmittedInputs.Has(frameID) {
+			pending = append(pending, key)
+		}
+		return true
+	})
+	return pending
+}
+
+// awaitFrameInputs 悲观锁步下判断 frameID 帧是否可以步进
+// 所有在线玩家都提交了该帧输入时立即返回 true；
+// 否则等待至多 DeterministicTimeout，超时后为迟到玩家补上空输入，
+// 通过 IGameWorld.OnInputTimeout 通知游戏世界，并允许步进
+func (room *Room) awaitFrameInputs(frameID uint32) bool {
+	pending := room.pendingInputPlayers(frameID)
+	if len(pending) == 0 {
+		room.frameWaitStart = time.Time{}
+		room.pruneSubmittedInputs(frameID)
+		return true
+	}
+
+	if room.frameWaitStart.IsZero() {
+		room.frameWaitStart = time.Now()
+		return false
+	}
+	if time.Since(room.frameWaitStart) < room.DeterministicTimeout() {
+		return false
+	}
+
+	log.Printf("⚠️ Room %d frame %d input timeout, stragglers: %v", room.ID, frameID, pending)
+	for _, uid := range pending {
+		// 以空输入代替迟到玩家本帧的输入
+		room.Game.OnReceiveClientInput(uid, &world.ClientInputData{Uid: uid, FrameId: frameID})
+		if c, ok := room.ClientsContainer.Clients.Load(uid); ok {
+			c.UpdateInputFrame(frameID)
+		}
+	}
+	room.Game.OnInputTimeout(frameID, pending)
+	room.frameWaitStart = time.Time{}
+	room.pruneSubmittedInputs(frameID)
+	return true
+}
+
+// pruneSubmittedInputs frameID 帧即将步进，之后不再需要各玩家对该帧及更早帧的输入记录
+func (room *Room) pruneSubmittedInputs(frameID uint32) {
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value != nil {
+			value.SubmittedInputs.PruneThrough(frameID)
+		}
+		return true
+	})
+}
@@ -0,0 +1,45 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/internal/defaults"
+	"slices"
+	"testing"
+)
+
+func TestPendingInputPlayersSkipsUnloadedPlayers(t *testing.T) {
+	room := newTestRoom(t, func(c *config.LockstepConfig) {
+		c.DeterministicLockstep = config.Int32Ptr(4)
+	})
+	loaded := addTestPlayer(t, room, 1)
+	loaded.IsLoaded = true
+	addTestPlayer(t, room, 2)
+
+	if pending := room.pendingInputPlayers(1); !slices.Equal(pending, []uint32{1}) {
+		t.Fatalf("pending = %v, want only the loaded player", pending)
+	}
+}
+
+func TestAwaitFrameInputsTracksEachFrame(t *testing.T) {
+	room := newTestRoom(t, func(c *config.LockstepConfig) {
+		c.DeterministicLockstep = config.Int32Ptr(8)
+		c.DeterministicTimeout = config.Uint32Ptr(60000)
+	})
+	room.Game = &defaults.DefaultGameWorld{}
+	player := addTestPlayer(t, room, 1)
+	player.IsLoaded = true
+
+	// 只提交了第 5 帧，第 1 至 4 帧仍在等待该玩家的输入
+	room.acceptClientInput(player, 5, []byte{1})
+	for frameID := uint32(1); frameID < 5; frameID++ {
+		if room.awaitFrameInputs(frameID) {
+			t.Fatalf("frame %d stepped without the player's input", frameID)
+		}
+	}
+	if !room.awaitFrameInputs(5) {
+		t.Fatalf("frame 5 did not step with the player's input")
+	}
+	if n := player.SubmittedInputs.Len(); n != 0 {
+		t.Fatalf("%d submitted frames left after stepping frame 5", n)
+	}
+}
@@ -0,0 +1,34 @@
+package room
+
+import (
+	"lockstep-core/src/constants"
+	"log"
+	"time"
+)
+
+// FrameInterval 获取本房间 lockstep 帧时钟的帧间隔
+func (room *Room) FrameInterval() time.Duration {
+	if room.LockstepConfig.FrameInterval == nil || *room.LockstepConfig.FrameInterval == 0 {
+		return constants.FrameIntervalMs * time.Millisecond
+	}
+	return time.Duration(*room.LockstepConfig.FrameInterval) * time.Millisecond
+}
+
+// startFrameClock 启动本房间的帧时钟
+// 仅在进入 InGame 阶段时调用，若已有时钟则先停止旧时钟
+func (room *Room) startFrameClock() {
+	room.stopFrameClock()
+	room.GameTicker = time.NewTicker(room.FrameInterval())
+	log.Printf("⏱️ Frame clock started for room %d (interval: %v)", room.ID, room.FrameInterval())
+}
+
+// stopFrameClock 停止本房间的帧时钟
+// 在 PostGame / Reset / Destroy 时调用，可重复调用
+func (room *Room) stopFrameClock() {
+	if room.GameTicker == nil {
+		return
+	}
+	room.GameTicker.Stop()
+	room.GameTicker = nil
+	log.Printf("⏱️ Frame clock stopped for room %d", room.ID)
+}
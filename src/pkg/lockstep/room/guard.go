@@ -0,0 +1,145 @@
+package room
+
+import (
+	"fmt"
+	"lockstep-core/src/config"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"log"
+	"time"
+)
+
+// MaxRequestSize 单个请求的最大字节数
+func (room *Room) MaxRequestSize() int {
+	if room.LockstepConfig.MaxRequestBytes == nil {
+		return config.DefaultMaxRequestBytes
+	}
+	return int(*room.LockstepConfig.MaxRequestBytes)
+}
+
+// RequestRateLimit 每个客户端每秒最多发送的请求数量，0 为不限制
+func (room *Room) RequestRateLimit() float64 {
+	if room.LockstepConfig.MaxRequestsPerSecond == nil {
+		return config.DefaultMaxRequestsPerSecond
+	}
+	return float64(*room.LockstepConfig.MaxRequestsPerSecond)
+}
+
+// InputSizeLimit 每一帧输入数据的最大字节数
+func (room *Room) InputSizeLimit() int {
+	if room.LockstepConfig.MaxInputBytesPerFrame == nil {
+		return config.DefaultMaxInputBytesPerFrame
+	}
+	return int(*room.LockstepConfig.MaxInputBytesPerFrame)
+}
+
+// InputFramesAheadLimit 输入的帧号最多领先服务端下一帧的帧数
+func (room *Room) InputFramesAheadLimit() uint32 {
+	if room.LockstepConfig.MaxInputFramesAhead == nil {
+		return config.DefaultMaxInputFramesAhead
+	}
+	return *room.LockstepConfig.MaxInputFramesAhead
+}
+
+// ViolationLimit 客户端在 client.ViolationWindow 内最多允许的违规次数，0 为不踢出
+func (room *Room) ViolationLimit() int {
+	if room.LockstepConfig.MaxViolations == nil {
+		return config.DefaultMaxViolations
+	}
+	return int(*room.LockstepConfig.MaxViolations)
+}
+
+// violate 记录客户端的一次违规，违规过多的客户端在下一次周期性维护时被踢出
+// 可在客户端的服务循环中调用
+func (room *Room) violate(c *client.Client, reason string) {
+	count := c.Guard.Violate(time.Now())
+	log.Printf("🛡️ Dropped request from client %d in room %d (%d in window): %s", c.GetID(), room.ID, count, reason)
+}
+
+// rejectRequest 记录一次违规并告知客户端请求被拒绝的原因
+// 回复按 client.ErrorReplyInterval 限频，持续发送违规请求的客户端不会换来同样数量的回复
+func (room *Room) rejectRequest(c *client.Client, code messages.ErrorCode, message string) {
+	room.violate(c, message)
+	if c.Guard.AllowErrorReply(time.Now()) {
+		room.sendError(c, code, message)
+	}
+}
+
+// admitRaw 在解析前检查请求的大小与频率，在客户端的服务循环中调用
+func (room *Room) admitRaw(c *client.Client, rawBytes []byte) bool {
+	if limit := room.MaxRequestSize(); len(rawBytes) > limit {
+		room.violate(c, fmt.Sprintf("request of %d bytes exceeds %d", len(rawBytes), limit))
+		return false
+	}
+	if !c.Guard.Allow(time.Now(), room.RequestRateLimit()) {
+		room.violate(c, "rate limit exceeded")
+		return false
+	}
+	return true
+}
+
+// admitRequest 检查解析后的请求是否合法，在房间主循环中调用
+// 检查请求在当前阶段是否合法，以及输入的大小与帧号
+// 帧输入走不可靠通道，阶段切换后仍在途的输入属于正常现象，直接丢弃而不计为违规
+func (room *Room) admitRequest(c *client.Client, req *messages.SessionRequest) bool {
+	stage := room.RoomStage.Load()
+	if !payloadAllowedInStage(req.GetPayload(), stage) {
+		if _, ok := req.GetPayload().(*messages.SessionRequest_InGameFrames); ok {
+			return false
+		}
+		room.rejectRequest(c, messages.ErrorCode_ERROR_CODE_ILLEGAL_STAGE,
+			fmt.Sprintf("request %T is not allowed in stage %#x", req.GetPayload(), uint32(stage)))
+		return false
+	}
+
+	frames := req.GetInGameFrames()
+	if frames == nil || room.IsReplay() || c.IsSpectator {
+		return true
+	}
+	sizeLimit := room.InputSizeLimit()
+	aheadBound := room.SyncData.NextFrameID.Load() + room.InputFramesAheadLimit()
+	reject := func(message string) bool {
+		room.rejectRequest(c, messages.ErrorCode_ERROR_CODE_INVALID_REQUEST, message)
+		return false
+	}
+	checkInput := func(frameID uint32, data []byte) bool {
+		if len(data) > sizeLimit {
+			return reject(fmt.Sprintf("input of %d bytes for frame %d exceeds %d", len(data), frameID, sizeLimit))
+		}
+		if frameID > aheadBound {
+			return reject(fmt.Sprintf("input frame %d is too far ahead (bound: %d)", frameID, aheadBound))
+		}
+		return true
+	}
+	if !checkInput(frames.GetFrameId(), frames.GetData()) {
+		return false
+	}
+	for _, input := range frames.GetInputs() {
+		if !checkInput(input.GetFrameId(), input.GetData()) {
+			return false
+		}
+	}
+	return true
+}
+
+// kickViolators 踢出当前窗口内违规过多的玩家与观战者
+func (room *Room) kickViolators() {
+	limit := room.ViolationLimit()
+	if limit <= 0 {
+		return
+	}
+	now := time.Now()
+	violators := []uint32{}
+	collect := func(uid uint32, c *client.Client) bool {
+		if c.Guard.Violations(now) > limit {
+			violators = append(violators, uid)
+		}
+		return true
+	}
+	room.ClientsContainer.Clients.Range(collect)
+	room.Spectators.Clients.Range(collect)
+
+	for _, uid := range violators {
+		room.kickPlayer(uid, messages.CloseReason_CLOSE_REASON_PROTOCOL_VIOLATION, "too many invalid requests")
+	}
+}
@@ -0,0 +1,47 @@
+package room
+
+import (
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"testing"
+)
+
+func TestAdmitRequestDropsLateFramesSilently(t *testing.T) {
+	room := newTestRoom(t, nil)
+	c := addTestPlayer(t, room, 1)
+	room.RoomStage.Store(constants.STAGE_PostGame)
+
+	frames := &messages.SessionRequest{Payload: &messages.SessionRequest_InGameFrames{
+		InGameFrames: &messages.RequestInGameFrames{FrameId: 10},
+	}}
+	if room.admitRequest(c, frames) {
+		t.Fatal("frame input was admitted outside InGame")
+	}
+	if got := c.Guard.TotalViolations(); got != 0 {
+		t.Fatalf("late frame input counted %d violations, want 0", got)
+	}
+
+	endGame := &messages.SessionRequest{Payload: &messages.SessionRequest_EndGame{}}
+	if room.admitRequest(c, endGame) {
+		t.Fatal("EndGame was admitted outside InGame")
+	}
+	if got := c.Guard.TotalViolations(); got != 1 {
+		t.Fatalf("stage mismatch counted %d violations, want 1", got)
+	}
+}
+
+func TestAdmitRequestRejectsOversizedInput(t *testing.T) {
+	room := newTestRoom(t, nil)
+	c := addTestPlayer(t, room, 1)
+	room.RoomStage.Store(constants.STAGE_InGame)
+
+	req := &messages.SessionRequest{Payload: &messages.SessionRequest_InGameFrames{
+		InGameFrames: &messages.RequestInGameFrames{FrameId: 1, Data: make([]byte, room.InputSizeLimit()+1)},
+	}}
+	if room.admitRequest(c, req) {
+		t.Fatal("oversized input was admitted")
+	}
+	if got := c.Guard.TotalViolations(); got != 1 {
+		t.Fatalf("oversized input counted %d violations, want 1", got)
+	}
+}
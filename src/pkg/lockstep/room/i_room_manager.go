@@ -1,5 +1,7 @@
 package room
 
+import "context"
+
 // IRoomManager 定义房间管理器的接口
 type IRoomManager interface {
 	// GetRoom 获取指定 ID 的房间
@@ -8,6 +10,9 @@ type IRoomManager interface {
 	// CreateRoom 创建一个新房间
 	CreateRoom(name string, key string) (*Room, error)
 
+	// CreateReplayRoom 创建一个播放回放文件的只读房间
+	CreateReplayRoom(path string, name string, key string) (*Room, error)
+
 	// RemoveRoom 删除一个房间
 	RemoveRoom(roomID uint32)
 
@@ -16,4 +21,10 @@ type IRoomManager interface {
 
 	// GetRoomCount 获取房间数量
 	GetRoomCount() int
+
+	// IsShuttingDown 是否正在关闭，关闭期间不再接受新房间与新玩家
+	IsShuttingDown() bool
+
+	// Shutdown 关闭所有房间，InGame 房间会在配置的时间内等待对局结束
+	Shutdown(ctx context.Context) error
 }
@@ -1,6 +1,8 @@
 package room
 
 import (
+	"fmt"
+	"lockstep-core/src/messages"
 	"lockstep-core/src/pkg/lockstep/client"
 )
 
@@ -35,7 +37,7 @@ func (r *RoomContextImpl) SendTo(uid uint32, data []byte) {
 	if r == nil || r.room == nil {
 		return
 	}
-	r.room.ClientsContainer.SendMessageToUser(data, uid)
+	r.room.ClientsContainer.SendDatagramToUser(data, uid)
 }
 
 func (r *RoomContextImpl) SendToMultiple(uids []uint32, data []byte) {
@@ -43,7 +45,7 @@ func (r *RoomContextImpl) SendToMultiple(uids []uint32, data []byte) {
 		return
 	}
 	for _, uid := range uids {
-		r.room.ClientsContainer.SendMessageToUser(data, uid)
+		r.room.ClientsContainer.SendDatagramToUser(data, uid)
 	}
 }
 
@@ -66,6 +68,13 @@ func (r *RoomContextImpl) GetAllPlayers() []uint32 {
 	return players
 }
 
+func (r *RoomContextImpl) GetOwner() (uint32, bool) {
+	if r == nil || r.room == nil {
+		return 0, false
+	}
+	return r.room.Owner()
+}
+
 func (r *RoomContextImpl) GetNextFrame() uint32 {
 	if r == nil || r.room == nil || r.room.SyncData == nil || r.room.SyncData.NextFrameID == nil {
 		return 0
@@ -73,25 +82,24 @@ func (r *RoomContextImpl) GetNextFrame() uint32 {
 	return r.room.SyncData.NextFrameID.Load()
 }
 
+func (r *RoomContextImpl) DumpFrameHistory(dir string) (string, error) {
+	if r == nil || r.room == nil {
+		return "", fmt.Errorf("room context is not bound to a room")
+	}
+	return r.room.DumpFrameHistory(dir)
+}
+
 func (r *RoomContextImpl) KickPlayer(uid uint32, reason string) {
 	if r == nil || r.room == nil {
 		return
 	}
-	// 从 ClientsContainer 中删除并关闭连接
-	if client, ok := r.room.ClientsContainer.Clients.Load(uid); ok {
-		if client != nil {
-			// 关闭会话
-			if client.Session != nil && client.Session.IsConnected() {
-				client.Session.CloseWithError(0, "you have been kicked: "+reason)
-			}
-		}
-	}
-	r.room.ClientsContainer.DelUser(uid)
+	// 告知玩家原因后从房间中移除并关闭连接
+	r.room.kickPlayer(uid, messages.CloseReason_CLOSE_REASON_KICKED, reason)
 }
 
 func (r *RoomContextImpl) DestroyRoom() {
 	if r == nil || r.room == nil {
 		return
 	}
-	r.room.Destroy()
+	r.room.DestroyWithReason(messages.CloseReason_CLOSE_REASON_GAME_ENDED, "game ended")
 }
@@ -0,0 +1,43 @@
+package room
+
+import (
+	"lockstep-core/src/types"
+)
+
+// refreshMetadata 从游戏世界获取房间附加信息并缓存，供房间列表跨 goroutine 读取
+// 回放房间使用回放文件中记录的附加信息
+// 仅在房间主循环中调用
+func (room *Room) refreshMetadata() {
+	if room.IsReplay() {
+		room.metadata.Store(&room.replay.source.Header.Metadata)
+		return
+	}
+	if room.Game == nil {
+		return
+	}
+	metadata := room.Game.GetRoomMetadata()
+	room.metadata.Store(&metadata)
+}
+
+// Info 获取房间的概要信息，可从任意 goroutine 调用
+// 游戏世界提供的附加信息来自房间主循环最近一次的缓存
+func (room *Room) Info() types.RoomInfo {
+	info := types.RoomInfo{
+		RoomID:         room.ID,
+		Name:           room.Name,
+		NeedKey:        room.HasKey(),
+		PlayerCount:    room.GetPlayerCount(),
+		MaxPlayers:     room.MaxClientPerRoom,
+		SpectatorCount: room.Spectators.GetPlayerCount(),
+		MaxSpectators:  room.MaxSpectators(),
+		GameState:      room.RoomStage.Load(),
+		Replay:         room.IsReplay(),
+	}
+	if owner, ok := room.Owner(); ok {
+		info.OwnerID = &owner
+	}
+	if metadata := room.metadata.Load(); metadata != nil {
+		info.Metadata = *metadata
+	}
+	return info
+}
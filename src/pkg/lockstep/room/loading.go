@@ -0,0 +1,93 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+	"slices"
+	"time"
+)
+
+// LoadingTimeoutDuration 加载阶段的超时时间，0 为一直等待
+func (room *Room) LoadingTimeoutDuration() time.Duration {
+	if room.LockstepConfig.LoadingTimeout == nil {
+		return config.DefaultLoadingTimeout * time.Millisecond
+	}
+	return time.Duration(*room.LockstepConfig.LoadingTimeout) * time.Millisecond
+}
+
+// onLoadedChanged 玩家的加载进度变化后调用
+// 广播加载人数与每位玩家的进度；加载阶段中所有玩家均已加载完毕时进入 InGame 阶段
+func (room *Room) onLoadedChanged() {
+	loadedPlayerIds := make([]uint32, 0)
+	progress := make([]*messages.LoadProgress, 0)
+	playerCount := 0
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value == nil {
+			return true
+		}
+		if value.IsLoaded {
+			loadedPlayerIds = append(loadedPlayerIds, key)
+		}
+		progress = append(progress, &messages.LoadProgress{PlayerId: key, Percent: value.LoadProgress})
+		playerCount++
+		return true
+	})
+	slices.SortFunc(progress, func(a, b *messages.LoadProgress) int {
+		return int(a.PlayerId) - int(b.PlayerId)
+	})
+	// 广播加载状态变更
+	innerLoadedResp := &messages.ResponseLoadedCountUpdate{
+		LoadedPlayerIds: loadedPlayerIds,
+		TotalCount:      uint32(playerCount),
+		Progress:        progress,
+	}
+	srespLoaded := &messages.SessionResponse{Payload: &messages.SessionResponse_LoadedCountUpdate{LoadedCountUpdate: innerLoadedResp}}
+	room.broadcastWithSpectators(srespLoaded, []uint32{})
+
+	if room.RoomStage.EqualTo(constants.STAGE_Loading) && playerCount > 0 && playerCount == len(loadedPlayerIds) {
+		// 所有玩家均已加载完毕，进入游戏阶段
+		// 由帧时钟驱动 world.Tick 进行游戏
+		room.changeStage(constants.STAGE_InGame, nil)
+	}
+}
+
+// expireLoading 加载超时时，按游戏世界的决定踢出未加载完毕的玩家或直接开始游戏
+// 在周期性维护中调用，精度为维护间隔
+func (room *Room) expireLoading() {
+	if room.loadingDeadline.IsZero() || time.Now().Before(room.loadingDeadline) {
+		return
+	}
+	room.loadingDeadline = time.Time{}
+	if !room.RoomStage.EqualTo(constants.STAGE_Loading) || room.Game == nil {
+		return
+	}
+
+	unloaded := make([]uint32, 0)
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value != nil && !value.IsLoaded {
+			unloaded = append(unloaded, key)
+		}
+		return true
+	})
+	if len(unloaded) == 0 {
+		return
+	}
+	slices.Sort(unloaded)
+
+	switch room.Game.OnLoadingTimeout(unloaded) {
+	case world.LoadingTimeoutKick:
+		log.Printf("⌛ Room %d loading timed out, kicking players %v", room.ID, unloaded)
+		for _, uid := range unloaded {
+			room.kickPlayer(uid, messages.CloseReason_CLOSE_REASON_KICKED, "not loaded in time")
+		}
+		room.onLoadedChanged()
+	default:
+		// 未加载完毕的玩家在加载完毕前不参与锁步的等待，见 pendingInputPlayers 与 HasAllPlayerSync
+		log.Printf("⌛ Room %d loading timed out, starting without players %v", room.ID, unloaded)
+		room.changeStage(constants.STAGE_InGame, nil)
+	}
+}
@@ -1,10 +1,14 @@
 package room
 
 import (
+	"cmp"
 	"lockstep-core/src/constants"
 	"lockstep-core/src/messages"
 	"lockstep-core/src/pkg/lockstep/client"
+	lockstep_sync "lockstep-core/src/pkg/lockstep/sync"
 	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+	"slices"
 )
 
 // 以下为 Room 的各个消息处理方法实现，均为非导出方法（首字母小写）
@@ -25,7 +29,7 @@ func (room *Room) handleInLobby(from *client.Client, payload *messages.SessionRe
 
 }
 
-// 请求进入 Preparing 阶段（房主／客户端发起）
+// 请求进入 Preparing 阶段（默认仅房主可发起）
 func (room *Room) handleToPreparing(from *client.Client, payload *messages.SessionRequest_ToPreparing) {
 	if room == nil || from == nil || payload == nil || payload.ToPreparing == nil {
 		return
@@ -33,50 +37,26 @@ func (room *Room) handleToPreparing(from *client.Client, payload *messages.Sessi
 	if room.Game == nil {
 		return
 	}
+	if !room.checkStagePermission(from, constants.STAGE_Preparing) {
+		return
+	}
 	if room.Game.OnHandleToPreparingStage(from.GetID(), payload.ToPreparing.GetData()) {
 		// 允许进入 Preparing 阶段
-		room.RoomStage.Store(constants.STAGE_Preparing)
+		room.changeStage(constants.STAGE_Preparing, nil)
 	}
 }
 
-// 玩家准备就绪
+// 玩家切换准备状态
 func (room *Room) handleReady(from *client.Client, payload *messages.SessionRequest_Ready) {
 	if room == nil || from == nil || payload == nil || payload.Ready == nil {
 		return
 	}
-	from.IsReady = true
+	from.IsReady = payload.Ready.GetIsReady()
 	if room.Game == nil {
 		return
 	}
-	room.Game.OnHandleReady(from.GetID(), payload.Ready.IsReady, payload.Ready.GetData())
-	var readyPlayerIds []uint32 = make([]uint32, 0)
-	var playerCount uint32 = 0
-	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
-		if value != nil && value.IsReady {
-			readyPlayerIds = append(readyPlayerIds, key)
-		}
-		playerCount++
-		return true
-	})
-	// 广播准备状态变更
-	innerReadyResp := &messages.ResponseReadyCountUpdate{
-		ReadyPlayerIds: readyPlayerIds,
-		TotalCount:     uint32(playerCount),
-	}
-	sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_ReadyCountUpdate{ReadyCountUpdate: innerReadyResp}}
-	room.BroadcastMessage(sresp, []uint32{})
-
-	if playerCount == uint32(len(readyPlayerIds)) {
-		// 所有玩家均已准备好
-		data := room.Game.OnHandleAllReady()
-		room.RoomStage.ForwardStage()
-		innerStage := &messages.ResponseStageChange{
-			NewStage: uint32(constants.STAGE_Loading),
-			Data:     data,
-		}
-		sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_StageChange{StageChange: innerStage}}
-		room.BroadcastMessage(sresp, []uint32{})
-	}
+	room.Game.OnHandleReady(from.GetID(), payload.Ready.GetIsReady(), payload.Ready.GetData())
+	room.onReadyChanged()
 }
 
 // 返回大厅
@@ -87,47 +67,37 @@ func (room *Room) handleToInLobby(from *client.Client, payload *messages.Session
 	if room.Game == nil {
 		return
 	}
+	if !room.checkStagePermission(from, constants.STAGE_InLobby) {
+		return
+	}
 	if room.Game.OnHandleToLobbyStage(from.GetID(), payload.ToInLobby.GetData()) {
 		// 允许返回大厅
-		room.RoomStage.Store(constants.STAGE_InLobby)
-		innerStage := &messages.ResponseStageChange{NewStage: uint32(constants.STAGE_InLobby)}
-		sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_StageChange{StageChange: innerStage}}
-		room.BroadcastMessage(sresp, []uint32{})
+		room.changeStage(constants.STAGE_InLobby, nil)
 	}
 }
 
-// 玩家加载完成
+// 玩家报告加载进度或加载完成
 func (room *Room) handleLoaded(from *client.Client, payload *messages.SessionRequest_Loaded) {
-	var playerCount uint32 = 0
 	if room == nil || from == nil || payload == nil || payload.Loaded == nil {
 		return
 	}
-	room.Game.OnHandleLoaded(from.GetID())
-	from.IsLoaded = true
-	var loadedPlayerIds []uint32 = make([]uint32, 0)
-	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
-		if value != nil && value.IsLoaded {
-			loadedPlayerIds = append(loadedPlayerIds, key)
+	if from.IsLoaded {
+		return
+	}
+	req := payload.Loaded
+	if !req.GetIsLoaded() && req.Progress != nil {
+		// 加载进度报告，只在进度变化时广播
+		progress := min(req.GetProgress(), 99)
+		if progress == from.LoadProgress {
+			return
 		}
-		playerCount++
-		return true
-	})
-	// 广播加载状态变更
-	innerLoadedResp := &messages.ResponseLoadedCountUpdate{
-		LoadedPlayerIds: loadedPlayerIds,
-		TotalCount:      uint32(playerCount),
-	}
-	srespLoaded := &messages.SessionResponse{Payload: &messages.SessionResponse_LoadedCountUpdate{LoadedCountUpdate: innerLoadedResp}}
-	room.BroadcastMessage(srespLoaded, []uint32{})
-
-	if playerCount == uint32(len(loadedPlayerIds)) {
-		// 所有玩家均已加载完毕，进入游戏阶段
-		// world 无方法需要调用，因为通过step来进行游戏开始
-		room.RoomStage.ForwardStage()
-		innerStage := &messages.ResponseStageChange{NewStage: uint32(constants.STAGE_InGame)}
-		sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_StageChange{StageChange: innerStage}}
-		room.BroadcastMessage(sresp, []uint32{})
+		from.LoadProgress = progress
+	} else {
+		room.Game.OnHandleLoaded(from.GetID())
+		from.IsLoaded = true
+		from.LoadProgress = 100
 	}
+	room.onLoadedChanged()
 }
 
 // 处理游戏中帧数据
@@ -138,15 +108,60 @@ func (room *Room) handleInGameFrames(from *client.Client, payload *messages.Sess
 	if room.Game == nil {
 		return
 	}
-	uid := from.GetID()
+	req := payload.InGameFrames
+	frameID := req.GetFrameId()
 	// 更新ack
-	from.LatestAckNextFrameID.Store(payload.InGameFrames.GetAckFrameId())
-	from.LatestNextFrameID.Store(payload.InGameFrames.GetFrameId())
+	room.updateAck(from, req.GetAckFrameId())
+	from.LatestNextFrameID.Store(frameID)
+	// 校验客户端上报的状态校验和
+	room.verifyChecksum(from, req)
+
+	if len(req.GetInputs()) == 0 {
+		// 未携带 data 的数据报只用于确认与心跳，不是输入，也不占用该帧的去重记录
+		if req.Data == nil {
+			return
+		}
+		// 单个输入，与冗余发送的输入一样按帧去重，重复的数据报不会被重复交给游戏世界
+		room.acceptClientInput(from, frameID, req.GetData())
+		return
+	}
 
-	room.Game.OnReceiveClientInput(uid, world.ClientInputData{
+	// 冗余发送的输入，按帧号升序交给游戏世界
+	inputs := req.GetInputs()
+	if len(inputs) > lockstep_sync.InputWindowSize {
+		inputs = inputs[len(inputs)-lockstep_sync.InputWindowSize:]
+	}
+	inputs = slices.Clone(inputs)
+	slices.SortStableFunc(inputs, func(a, b *messages.InputEntry) int {
+		return cmp.Compare(a.GetFrameId(), b.GetFrameId())
+	})
+	for _, input := range inputs {
+		room.acceptClientInput(from, input.GetFrameId(), input.GetData())
+	}
+}
+
+// acceptClientInput 将玩家某一帧的输入交给游戏世界
+// 每名玩家每一帧只接受一次输入，已收到过的帧的输入会被丢弃
+func (room *Room) acceptClientInput(from *client.Client, frameID uint32, data []byte) {
+	uid := from.GetID()
+	if from.Inputs.Has(frameID) {
+		return
+	}
+	if room.IsDeterministic() && !room.AcceptDeterministicInput(frameID) {
+		log.Printf("⚠️ Room %d dropped input of player %d for frame %d (next frame: %d)",
+			room.ID, uid, frameID, room.SyncData.NextFrameID.Load())
+		return
+	}
+	from.Inputs.Mark(frameID)
+	from.UpdateInputFrame(frameID)
+	if room.IsDeterministic() {
+		from.SubmittedInputs.Mark(frameID)
+	}
+
+	room.Game.OnReceiveClientInput(uid, &world.ClientInputData{
 		Uid:     uid,
-		FrameId: payload.InGameFrames.FrameId,
-		Data:    payload.InGameFrames.GetData(),
+		FrameId: frameID,
+		Data:    data,
 	})
 }
 
@@ -170,11 +185,11 @@ func (room *Room) handleEndGame(from *client.Client, payload *messages.SessionRe
 	if room.Game == nil {
 		return
 	}
+	if !room.checkStagePermission(from, constants.STAGE_PostGame) {
+		return
+	}
 	if room.Game.OnHandleEndGame(from.GetID(), payload.EndGame.GetStatusCode(), payload.EndGame.GetData()) {
-		room.RoomStage.Store(constants.STAGE_PostGame)
-		innerStage := &messages.ResponseStageChange{NewStage: uint32(constants.STAGE_PostGame)}
-		sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_StageChange{StageChange: innerStage}}
-		room.BroadcastMessage(sresp, []uint32{})
+		room.changeStage(constants.STAGE_PostGame, nil)
 	}
 }
 
@@ -188,10 +203,7 @@ func (room *Room) handlePostGameData(from *client.Client, payload *messages.Sess
 	}
 	backToLobby := room.Game.OnHandlePostGameData(from.GetID(), payload.PostGameData.GetData())
 	if backToLobby {
-		room.RoomStage.Store(constants.STAGE_InLobby)
-		innerStage := &messages.ResponseStageChange{NewStage: uint32(constants.STAGE_InLobby)}
-		sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_StageChange{StageChange: innerStage}}
-		room.BroadcastMessage(sresp, []uint32{})
+		room.changeStage(constants.STAGE_InLobby, nil)
 	}
 }
 
@@ -0,0 +1,54 @@
+package room
+
+import (
+	"lockstep-core/src/internal/defaults"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/world"
+	"testing"
+)
+
+// inputCountingWorld 统计收到的输入
+type inputCountingWorld struct {
+	defaults.DefaultGameWorld
+	inputs []uint32
+	data   [][]byte
+}
+
+func (w *inputCountingWorld) OnReceiveClientInput(uid uint32, data *world.ClientInputData) {
+	w.inputs = append(w.inputs, data.GetFrameId())
+	w.data = append(w.data, data.GetData())
+}
+
+func TestHandleInGameFramesDedupesDuplicateDatagrams(t *testing.T) {
+	room := newTestRoom(t, nil)
+	game := &inputCountingWorld{}
+	room.Game = game
+	player := addTestPlayer(t, room, 1)
+
+	// 只用于确认的数据报不占用该帧的输入
+	ackOnly := &messages.SessionRequest_InGameFrames{
+		InGameFrames: &messages.RequestInGameFrames{FrameId: 3, AckFrameId: 2},
+	}
+	room.handleInGameFrames(player, ackOnly)
+
+	single := &messages.SessionRequest_InGameFrames{
+		InGameFrames: &messages.RequestInGameFrames{FrameId: 3, Data: []byte{1}},
+	}
+	room.handleInGameFrames(player, single)
+	room.handleInGameFrames(player, single)
+
+	redundant := &messages.SessionRequest_InGameFrames{
+		InGameFrames: &messages.RequestInGameFrames{FrameId: 4, Inputs: []*messages.InputEntry{
+			{FrameId: 3, Data: []byte{1}},
+			{FrameId: 4, Data: []byte{2}},
+		}},
+	}
+	room.handleInGameFrames(player, redundant)
+
+	if len(game.inputs) != 2 || game.inputs[0] != 3 || game.inputs[1] != 4 {
+		t.Fatalf("inputs = %v, want [3 4]", game.inputs)
+	}
+	if string(game.data[0]) != "\x01" {
+		t.Fatalf("frame 3 input = %v, want the datagram carrying data", game.data[0])
+	}
+}
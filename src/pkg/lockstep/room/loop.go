@@ -13,6 +13,9 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// housekeepingInterval 房间周期性维护的间隔
+const housekeepingInterval = 500 * time.Millisecond
+
 // Run 房间状态机主循环
 func (room *Room) Run() {
 	defer func() {
@@ -27,6 +30,14 @@ func (room *Room) Run() {
 
 	// 初始房间状态，大厅中等待玩家
 	room.RoomStage.Store(constants.STAGE_InLobby)
+	room.refreshMetadata()
+	if room.IsReplay() {
+		room.startReplay()
+	}
+
+	// 周期性维护定时器
+	housekeeping := time.NewTicker(housekeepingInterval)
+	defer housekeeping.Stop()
 
 	/* 状态机主循环
 	根据用户输入和房间的当前状态来进行分支
@@ -65,49 +76,64 @@ func (room *Room) Run() {
 		// 3. 处理定时器事件，仅在 InGame 状态下有效
 		case <-tickerChan:
 			room.stepGameTick()
+
+		// 4. 周期性维护，例如移除等待重连超时的玩家
+		case <-housekeeping.C:
+			room.housekeep()
+
+		// 5. 外部请求关闭房间，例如空闲房间回收
+		case req := <-room.closeRequests:
+			room.DestroyWithReason(req.code, req.reason)
+
+		// 6. 房间即将被关闭的预告，例如服务器关闭时
+		case notice := <-room.closeNotices:
+			room.broadcastClosePending(notice)
 		}
 	}
 }
 
 // handleRegister 处理玩家注册
+// 新玩家加入房间；携带重连令牌的玩家则换回其断线前的席位
 func (room *Room) handleRegister(player *client.Client) {
 	log.Printf("🔵 Processing registration for player %d", player.GetID())
 
 	// 更新房间活跃时间
 	room.UpdateActiveTime()
+	player.Outbox.SetCapacity(room.SendQueueCapacity())
 
-	log.Printf("🔵 Room is in lobby state, adding player %d", player.GetID())
-	// 向 context 中注册用户
-	room.ClientsContainer.AddUser(player)
+	if player.IsSpectator {
+		room.handleSpectatorRegister(player)
+		return
+	}
+
+	// 先生成重连令牌，失败时只拒绝本次加入，不改动房间中已有的玩家
+	// 重连的玩家保持断线等待重连状态，新玩家释放已分配的 ID
 	reconnKey, err := room.JwtService.GenerateToken(player.GetID(), room.ID)
 	if err != nil {
-		innerResp := &messages.ResponseJoin{
-			Code: 500,
-			Payload: &messages.ResponseJoin_Fail{
-				Fail: &messages.ResponseJoinFail{
-					Message: fmt.Sprintf("Fail to Generate reconnect token: %s", err.Error()),
-				},
-			},
-		}
-		sresp := &messages.SessionResponse{
-			Payload: &messages.SessionResponse_Join{Join: innerResp},
-		}
-		b, err := proto.Marshal(sresp)
-		if err == nil {
-			room.SendMessageToUserByPlayer(b, player)
-		}
 		log.Printf("🔴 Failed to generate reconnect token for player %d: %v", player.GetID(), err)
+		if !player.IsReconnected {
+			room.ClientsContainer.FreeUserID(player.GetID())
+		}
+		room.rejectJoin(player, fmt.Sprintf("Fail to Generate reconnect token: %s", err.Error()))
 		return
 	}
+
+	if player.IsReconnected {
+		existing, ok := room.resumePlayer(player)
+		if !ok {
+			room.rejectJoin(player, "reconnect grace period expired")
+			return
+		}
+		player = existing
+	} else {
+		log.Printf("🔵 Room is in lobby state, adding player %d", player.GetID())
+		// 向 context 中注册用户
+		room.ClientsContainer.AddUser(player)
+		room.assignOwnerOnJoin(player.GetID())
+	}
+
 	extraData := room.Game.OnPlayerJoin(player.GetID(), player.IsReconnected)
 	// 发送欢迎消息
-	roomInfo := &messages.RoomInfo{
-		RoomKey:        room.key,
-		MaxPlayers:     int32(room.MaxClientPerRoom),
-		CurrentPlayers: int32(room.GetPlayerCount()),
-		PlayerIDs:      room.Clients.ToSlice(),
-		Data:           extraData,
-	}
 	innerResp := &messages.ResponseJoin{
 		Code: 200,
 		Payload: &messages.ResponseJoin_Success{
@@ -115,7 +141,7 @@ func (room *Room) handleRegister(player *client.Client) {
 				RoomID:         room.ID,
 				MyID:           player.GetID(),
 				ReconnectToken: reconnKey,
-				RoomInfo:       roomInfo,
+				RoomInfo:       room.buildRoomInfo(extraData),
 			},
 		},
 	}
@@ -126,40 +152,123 @@ func (room *Room) handleRegister(player *client.Client) {
 	}
 
 	// 制作当前 peers 信息并广播房间信息
-	room.BroadcastMessage(sresp, []uint32{})
+	// 重连令牌只发给本人，其他玩家只收到房间信息变更
+	room.broadcastRoomInfoChanged([]uint32{player.GetID()})
+	if room.IsReplay() {
+		room.sendReplayIntro(player)
+	}
+	if player.IsReconnected {
+		// 因断线自动暂停的对局在断线玩家均已重连后恢复
+		room.resumeAfterDisconnect()
+	}
+	room.sendPauseState(player)
+
+	// 开始接收该玩家的消息
+	go room.StartServeClient(player)
 
 	log.Printf("🔵 Player %d successfully registered", player.GetID())
 
 }
 
+// rejectJoin 向加入失败的玩家发送失败原因并关闭其会话
+func (room *Room) rejectJoin(player *client.Client, message string) {
+	innerResp := &messages.ResponseJoin{
+		Code: 500,
+		Payload: &messages.ResponseJoin_Fail{
+			Fail: &messages.ResponseJoinFail{
+				Message: message,
+			},
+		},
+	}
+	sresp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_Join{Join: innerResp},
+	}
+	b, err := proto.Marshal(sresp)
+	if err != nil {
+		player.Close()
+	} else {
+		player.CloseWithMessage(b, uint32(messages.CloseReason_CLOSE_REASON_UNSPECIFIED), message)
+	}
+	log.Printf("🔴 Rejected player %d joining room %d: %s", player.GetID(), room.ID, message)
+}
+
 // handleUnregister 处理玩家注销
+// 在配置了重连等待时间时，玩家进入断线等待重连状态而非立即移除
 func (room *Room) handleUnregister(player *client.Client) {
 	if player == nil || player.Session == nil {
 		return
 	}
+	if player.IsSpectator {
+		room.removeSpectator(player)
+		return
+	}
+
+	current, ok := room.ClientsContainer.Clients.Load(player.GetID())
+	if !ok || current != player {
+		// 玩家已被移除（例如被踢出），只需确保连接关闭
+		player.Close()
+		return
+	}
+	if player.IsDisconnected() {
+		return
+	}
 
+	if room.ReconnectGracePeriodDuration() > 0 {
+		room.markDisconnected(player)
+		return
+	}
+	room.removePlayer(player)
+}
+
+// removePlayer 将玩家从房间中彻底移除并通知游戏世界
+func (room *Room) removePlayer(player *client.Client) {
 	log.Printf("🟡 Unregistering player %d", player.GetID())
 	room.ClientsContainer.DelUser(player.GetID())
+	room.migrateOwner(player.GetID())
 
-	// 关闭连接
-	if player.Session.IsConnected() {
-		player.Session.Close()
-	}
+	// 关闭连接，已安排的最后一条消息（例如被踢出的原因）仍会送出
+	player.Close()
 
 	room.Game.OnPlayerLeave(player.GetID())
 
 	// 广播人数变化
-	roomInfo := &messages.RoomInfo{
+	room.broadcastRoomInfoChanged([]uint32{player.GetID()})
+}
+
+// buildRoomInfo 制作当前房间信息
+func (room *Room) buildRoomInfo(extraData []byte) *messages.RoomInfo {
+	info := &messages.RoomInfo{
 		RoomKey:        room.key,
 		MaxPlayers:     int32(room.MaxClientPerRoom),
 		CurrentPlayers: int32(room.GetPlayerCount()),
 		PlayerIDs:      room.Clients.ToSlice(),
+		Data:           extraData,
+	}
+	if owner, ok := room.Owner(); ok {
+		info.OwnerID = &owner
 	}
+	return info
+}
+
+// broadcastRoomInfoChanged 广播房间信息变更
+func (room *Room) broadcastRoomInfoChanged(excludeIDs []uint32) {
 	innerRoomInfoResp := &messages.ResponseRoomInfoChanged{
-		RoomInfo: roomInfo,
+		RoomInfo: room.buildRoomInfo(nil),
 	}
 	srespRoomInfo := &messages.SessionResponse{Payload: &messages.SessionResponse_RoomInfoChanged{RoomInfoChanged: innerRoomInfoResp}}
-	room.BroadcastMessage(srespRoomInfo, []uint32{player.GetID()})
+	room.broadcastWithSpectators(srespRoomInfo, excludeIDs)
+}
+
+// housekeep 房间的周期性维护，与帧时钟无关，在所有阶段均运行
+func (room *Room) housekeep() {
+	room.expireDisconnectedPlayers()
+	room.disconnectSaturatedClients()
+	room.kickViolators()
+	room.expireReadyCountdown()
+	room.expireLoading()
+	room.expirePause()
+	room.resumeAfterDisconnect()
+	room.refreshMetadata()
 }
 
 // handlePlayerMessage 处理玩家消息
@@ -175,12 +284,28 @@ func (room *Room) handlePlayerMessage(msg *client.ClientMessage) {
 		}
 	}()
 
+	// 丢弃当前阶段非法或内容越界的请求
+	if !room.admitRequest(msg.Client, msg.SessionRequest) {
+		return
+	}
+
 	// 更新房间活跃时间 - 任何玩家消息都表示房间是活跃的
 	room.UpdateActiveTime()
 
 	// 解析 用户消息并进行分支处理
 	payload := msg.SessionRequest.Payload
 
+	// 观战者不能提交输入或请求切换阶段
+	if msg.Client.IsSpectator {
+		room.handleSpectatorMessage(msg.Client, payload)
+		return
+	}
+	// 回放房间是只读的
+	if room.IsReplay() {
+		room.handleReplayMessage(msg.Client, payload)
+		return
+	}
+
 	// 对 oneof 字段的具体类型进行 switch 并交由相应的 handler 处理
 	switch p := payload.(type) {
 	case *messages.SessionRequest_InLobby:
@@ -201,108 +326,139 @@ func (room *Room) handlePlayerMessage(msg *client.ClientMessage) {
 		room.handleEndGame(msg.Client, p)
 	case *messages.SessionRequest_PostGameData:
 		room.handlePostGameData(msg.Client, p)
+	case *messages.SessionRequest_TransferOwner:
+		room.handleTransferOwner(msg.Client, p)
+	case *messages.SessionRequest_SubscribeChunks:
+		room.handleSubscribeChunks(msg.Client, p)
+	case *messages.SessionRequest_UnsubscribeChunks:
+		room.handleUnsubscribeChunks(msg.Client, p)
+	case *messages.SessionRequest_Pause:
+		room.handlePause(msg.Client, p)
+	case *messages.SessionRequest_Resume:
+		room.handleResume(msg.Client, p)
 	default:
 		// unknown type - ignore
 	}
 }
 
-// runGameTick 定时器触发的游戏逻辑帧
-// 乐观lockstep, 不等待迟到帧
+// stepGameTick 帧时钟触发的游戏逻辑帧
+// 乐观lockstep 不等待迟到帧；悲观lockstep 等待所有玩家输入或超时，见 deterministic.go
+// 每帧固定按 world.Tick -> world.GetFrameData 的顺序调用游戏世界
+// 回放房间改为从回放中取帧，见 replay.go
 func (room *Room) stepGameTick() {
+	if room.IsReplay() {
+		room.stepReplayTick()
+		return
+	}
+	// 通知上一帧以来检测到的不同步
+	room.flushDesyncs()
+
 	// 仍然没有玩家在线，即全部离开或断开，那么等待，跳过本次
 	if room.ClientsContainer.GetPlayerCount() == 0 {
 		log.Printf("⚠️ No players online in room %v, skipping game tick", room.ID)
 		return
 	}
 
-	// 如果没有启用乐观锁，判断是否停止等待
-	if *room.LockstepConfig.MaxDelayFrames >= 0 && !room.HasAllPlayerSync() {
-		// 跳过本次
+	// 这一次step行为的目标帧号
+	nextRenderFrame := room.SyncData.NextFrameID.Load()
+
+	if room.IsDeterministic() {
+		// 悲观锁步，等待所有在线玩家提交本帧输入或超时
+		if !room.awaitFrameInputs(nextRenderFrame) {
+			return
+		}
+	} else if *room.LockstepConfig.MaxDelayFrames >= 0 && !room.HasAllPlayerSync() {
+		// 乐观锁步，有玩家延迟超过容忍量，跳过本次
 		return
 	}
 
 	// 本次frame step行为将有效，更新最后活动时间
-	room.LastActiveTime = time.Now()
-	// 这一次step行为的目标帧号
-	nextRenderFrame := room.SyncData.NextFrameID.Load()
+	room.touchActiveTime()
 
 	// 预组装所有帧数据以优化发送
-	var oldestAck uint32 = 0xFFFFFFFF
-	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
-		ack := value.LatestAckNextFrameID.Load()
-		if ack < nextRenderFrame {
-			oldestAck = ack
-		}
-		return true
-	})
+	oldestAck := room.oldestPlayerAck(nextRenderFrame)
 
-	// 步进到下一帧所需的FrameData
+	// 固定顺序：先推进游戏世界，再获取步进到下一帧所需的FrameData
+	room.Game.Tick()
+	// 游戏世界可能在 Tick 中通过 DestroyRoom 销毁了房间
+	if room.Game == nil || room.RoomStage.EqualTo(constants.STAGE_CLOSED) {
+		return
+	}
 	frameData := room.Game.GetFrameData(nextRenderFrame, world.WorldOptions{
 		ChunkID: 0,
 	})
+	frameData.FrameId = nextRenderFrame
 	frameData.OldestAckFrameId = oldestAck
+	room.recordChecksum(nextRenderFrame)
 
 	room.SyncData.StoreFrame(nextRenderFrame, &frameData)
+	// 再获取其他被订阅的 chunk 的帧数据，见 chunks.go
+	room.produceChunkFrames(nextRenderFrame, oldestAck)
+	// 按间隔生成快照以供追帧
+	room.takeSnapshot(nextRenderFrame)
+
+	// 淘汰已被所有客户端确认的历史帧，观战者所需的延迟帧同样需要保留
+	pruneBound := oldestAck
+	if oldestAck == 0xFFFFFFFF {
+		pruneBound = nextRenderFrame - 1
+	}
+	if spectatorBound, ok := room.spectatorPruneBound(nextRenderFrame); ok && spectatorBound < pruneBound {
+		pruneBound = spectatorBound
+	}
+	room.SyncData.Prune(pruneBound)
 
 	// 步进，防止耗时的发送操作阻塞逻辑更新
 	room.SyncData.NextFrameID.Add(1)
 
-	if oldestAck == 0xFFFFFFFF {
-		// 发送空
-		resp := &messages.SessionResponse{
-			Payload: &messages.SessionResponse_InGameFrames{
-				InGameFrames: &messages.ResponseInGameFrames{
-					Frames: []*messages.FrameData{},
-				},
-			},
-		}
-		room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
-			go func(client *client.Client) {
-				data, err := proto.Marshal(resp)
-				if err != nil {
-					log.Printf("Failed to marshal empty frame data for client %d: %v", client.GetID(), err)
-					return
-				}
-				client.Write(data)
-			}(value)
-			return true
-		})
-		// 结束发送空
-		return
-	}
+	// 观战者收到延迟后的帧
+	room.sendSpectatorFrames(nextRenderFrame)
 
-	allFrames := make([]*messages.FrameData, 0, nextRenderFrame-oldestAck)
-	for i := oldestAck + 1; i <= nextRenderFrame; i++ {
-		if frame, ok := room.SyncData.GetFrame(i); ok {
-			allFrames = append(allFrames, (*messages.FrameData)(frame))
+	room.broadcastFrames(nextRenderFrame, oldestAck)
+}
+
+// oldestPlayerAck 所有玩家中尚未追上 nextRenderFrame 的最旧 ack，均已追上时为 0xFFFFFFFF
+func (room *Room) oldestPlayerAck(nextRenderFrame uint32) uint32 {
+	var oldestAck uint32 = 0xFFFFFFFF
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		ack := value.LatestAckNextFrameID.Load()
+		if ack < nextRenderFrame && ack < oldestAck {
+			oldestAck = ack
 		}
+		return true
+	})
+	return oldestAck
+}
+
+// broadcastFrames 向每位在线玩家发送其 ack 之后直到 nextRenderFrame 的帧
+// 实际发送的帧由各玩家的链路估计决定，见 datagram.go
+// 已追上的玩家收到空帧包，每个帧包都附带服务端已收到的该玩家输入的最新帧号
+func (room *Room) broadcastFrames(nextRenderFrame, oldestAck uint32) {
+	// 按 chunk 预组装，帧号升序，已被淘汰的帧不在其中
+	allFrames := map[int][]*messages.FrameData{}
+	if oldestAck != 0xFFFFFFFF {
+		allFrames = room.collectChunkFrames(room.activeChunks(), oldestAck+1, nextRenderFrame)
 	}
 
 	// 为每位用户发送ack至目前的帧
 	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
-		go func(client *client.Client) {
-			// 用户已经确认了“步进到ack”所需的帧数据，
-			// 需要向他传递 "步进到ack+1", "步进到ack+2" ... "步进到nextRenderFrame" 的所有帧数据
-			ack := client.LatestAckNextFrameID.Load()
-			if ack >= nextRenderFrame {
-				return
-			}
-			frames := allFrames[ack-oldestAck : nextRenderFrame-oldestAck]
-			resp := &messages.SessionResponse{
-				Payload: &messages.SessionResponse_InGameFrames{
-					InGameFrames: &messages.ResponseInGameFrames{
-						Frames: frames,
-					},
-				},
-			}
-			data, err := proto.Marshal(resp)
-			if err != nil {
-				log.Printf("Failed to marshal frame data for client %d: %v", client.GetID(), err)
-				return
+		if value.IsDisconnected() {
+			// 断线玩家重连后通过快照或历史帧追帧
+			return true
+		}
+		// 用户已经确认了“步进到ack”所需的帧数据，
+		// 需要向他传递 "步进到ack+1", "步进到ack+2" ... "步进到nextRenderFrame" 的所有帧数据
+		ack := value.LatestAckNextFrameID.Load()
+		frames := []*messages.FrameData{}
+		if ack < nextRenderFrame {
+			// 严重落后或所需帧已被淘汰，改为发送快照
+			if room.trySnapshotCatchUp(value, ack, nextRenderFrame) {
+				return true
 			}
-			client.Write(data)
-		}(value)
+			frames = framesFor(value, ack, allFrames)
+		}
+		// 按冗余度挑选并拆分为多个数据报，见 datagram.go
+		// 数据报进入各客户端的出站队列，不会阻塞房间主循环
+		room.sendFrames(value, frames, nextRenderFrame, value.LatestInputFrameID.Load())
 		return true
 	})
-
 }
@@ -0,0 +1,33 @@
+package room
+
+import (
+	"lockstep-core/src/constants"
+	"lockstep-core/src/internal/defaults"
+	"testing"
+)
+
+// destroyingWorld 在 Tick 中销毁房间的游戏世界
+type destroyingWorld struct {
+	defaults.DefaultGameWorld
+	room *Room
+}
+
+func (w *destroyingWorld) Tick() {
+	NewRoomContextImpl(w.room).DestroyRoom()
+}
+
+func TestStepGameTickStopsAfterDestroyInTick(t *testing.T) {
+	room := newTestRoom(t, nil)
+	addTestPlayer(t, room, 1)
+	room.Game = &destroyingWorld{room: room}
+	room.RoomStage.Store(constants.STAGE_InGame)
+
+	room.stepGameTick()
+
+	if !room.RoomStage.EqualTo(constants.STAGE_CLOSED) {
+		t.Fatalf("stage = %d, want CLOSED", room.RoomStage.Load())
+	}
+	if _, ok := room.SyncData.GetFrame(room.SyncData.NextFrameID.Load()); ok {
+		t.Fatalf("frame stored after the room was destroyed")
+	}
+}
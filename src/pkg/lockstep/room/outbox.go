@@ -0,0 +1,66 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"log"
+	"time"
+)
+
+// SendQueueCapacity 每个客户端出站队列的容量
+func (room *Room) SendQueueCapacity() int {
+	if room.LockstepConfig.SendQueueSize == nil || *room.LockstepConfig.SendQueueSize == 0 {
+		return config.DefaultSendQueueSize
+	}
+	return int(*room.LockstepConfig.SendQueueSize)
+}
+
+// SendQueueSaturationTimeoutDuration 出站队列持续饱和多久后断开客户端，0 为不断开
+func (room *Room) SendQueueSaturationTimeoutDuration() time.Duration {
+	if room.LockstepConfig.SendQueueSaturationTimeout == nil {
+		return config.DefaultSendQueueSaturation * time.Millisecond
+	}
+	return time.Duration(*room.LockstepConfig.SendQueueSaturationTimeout) * time.Millisecond
+}
+
+// disconnectSaturatedClients 断开出站队列持续饱和的玩家与观战者
+// 只关闭其会话，之后与普通断线一样经由 unregister 处理，玩家可在等待期内重连
+func (room *Room) disconnectSaturatedClients() {
+	timeout := room.SendQueueSaturationTimeoutDuration()
+	if timeout <= 0 {
+		return
+	}
+	now := time.Now()
+	check := func(uid uint32, c *client.Client) bool {
+		if c.IsDisconnected() || c.Outbox.SaturatedFor(now) < timeout {
+			return true
+		}
+		sess := c.GetSession()
+		if sess == nil || !sess.IsConnected() {
+			return true
+		}
+		stats := c.Outbox.Stats()
+		log.Printf("🐢 Client %d in room %d is too slow (queued: %d datagrams, %d control, dropped: %d), disconnecting",
+			uid, room.ID, stats.QueuedDatagrams, stats.QueuedControl, stats.Dropped)
+		// 积压的消息已无意义，重连后会重新收到完整的房间状态
+		c.Outbox.Clear()
+		code := messages.CloseReason_CLOSE_REASON_SLOW_CONNECTION
+		c.CloseWithMessage(kickedMessage(code, "send queue saturated"), uint32(code), "slow connection")
+		return true
+	}
+	room.ClientsContainer.Clients.Range(check)
+	room.Spectators.Clients.Range(check)
+}
+
+// OutboxStats 获取本房间所有玩家与观战者的出站队列统计，用于监控
+func (room *Room) OutboxStats() map[uint32]client.OutboxStats {
+	stats := make(map[uint32]client.OutboxStats)
+	collect := func(uid uint32, c *client.Client) bool {
+		stats[uid] = c.Outbox.Stats()
+		return true
+	}
+	room.ClientsContainer.Clients.Range(collect)
+	room.Spectators.Clients.Range(collect)
+	return stats
+}
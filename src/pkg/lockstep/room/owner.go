@@ -0,0 +1,121 @@
+package room
+
+import (
+	"fmt"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"log"
+	"slices"
+)
+
+// noOwner 表示房间当前没有房主
+const noOwner = ^uint32(0)
+
+// Owner 获取房主的玩家 ID，房间内没有玩家时返回 false
+// 可从任意 goroutine 调用
+func (room *Room) Owner() (uint32, bool) {
+	uid := room.ownerID.Load()
+	return uid, uid != noOwner
+}
+
+// IsOwner 判断玩家是否为房主
+func (room *Room) IsOwner(uid uint32) bool {
+	owner, ok := room.Owner()
+	return ok && owner == uid
+}
+
+// setOwner 设置房主，仅在房间主循环中调用
+func (room *Room) setOwner(uid uint32) {
+	old := room.ownerID.Swap(uid)
+	if old == uid {
+		return
+	}
+	if uid == noOwner {
+		log.Printf("👑 Room %d has no owner now", room.ID)
+	} else {
+		log.Printf("👑 Room %d owner changed: %d -> %d", room.ID, old, uid)
+	}
+}
+
+// assignOwnerOnJoin 房间没有房主时，由加入的玩家成为房主
+func (room *Room) assignOwnerOnJoin(uid uint32) {
+	if _, ok := room.Owner(); !ok {
+		room.setOwner(uid)
+	}
+}
+
+// migrateOwner 房主离开房间后，将房主身份转移给剩余玩家中 ID 最小的在线玩家，
+// 没有在线玩家时转移给等待重连的玩家，房间为空时清空房主
+func (room *Room) migrateOwner(leftUID uint32) {
+	if !room.IsOwner(leftUID) {
+		return
+	}
+
+	var online, disconnected []uint32
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value == nil {
+			return true
+		}
+		if value.IsDisconnected() {
+			disconnected = append(disconnected, key)
+		} else {
+			online = append(online, key)
+		}
+		return true
+	})
+
+	switch {
+	case len(online) > 0:
+		room.setOwner(slices.Min(online))
+	case len(disconnected) > 0:
+		room.setOwner(slices.Min(disconnected))
+	default:
+		room.setOwner(noOwner)
+	}
+}
+
+// checkStagePermission 判断玩家是否有权请求切换到目标阶段
+// 默认仅房主可以请求，具体策略由游戏世界的 CouldRequestStage 决定
+func (room *Room) checkStagePermission(from *client.Client, target constants.Stage) bool {
+	uid := from.GetID()
+	current := room.RoomStage.Load()
+	if !current.CanTransitionTo(target) {
+		log.Printf("⚠️ Room %d denied illegal stage request 0x%x -> 0x%x from player %d",
+			room.ID, uint32(current), uint32(target), uid)
+		room.sendError(from, messages.ErrorCode_ERROR_CODE_ILLEGAL_TRANSITION,
+			fmt.Sprintf("cannot change stage from %#x to %#x", uint32(current), uint32(target)))
+		return false
+	}
+	if room.Game.CouldRequestStage(uid, room.IsOwner(uid), target) {
+		return true
+	}
+	log.Printf("⚠️ Room %d denied stage request 0x%x from player %d (owner: %v)",
+		room.ID, uint32(target), uid, room.IsOwner(uid))
+	room.sendError(from, messages.ErrorCode_ERROR_CODE_PERMISSION_DENIED,
+		fmt.Sprintf("not allowed to request stage %#x", uint32(target)))
+	return false
+}
+
+// handleTransferOwner 房主将房主身份转让给房间内的另一名玩家
+func (room *Room) handleTransferOwner(from *client.Client, payload *messages.SessionRequest_TransferOwner) {
+	if room == nil || from == nil || payload == nil || payload.TransferOwner == nil {
+		return
+	}
+	uid := from.GetID()
+	target := payload.TransferOwner.GetNewOwnerId()
+	if !room.IsOwner(uid) {
+		log.Printf("⚠️ Room %d denied owner transfer from non-owner player %d", room.ID, uid)
+		room.sendError(from, messages.ErrorCode_ERROR_CODE_PERMISSION_DENIED, "only the room owner can transfer ownership")
+		return
+	}
+	if _, ok := room.ClientsContainer.Clients.Load(target); !ok || target == uid {
+		log.Printf("⚠️ Room %d denied owner transfer from %d to invalid player %d", room.ID, uid, target)
+		room.sendError(from, messages.ErrorCode_ERROR_CODE_INVALID_REQUEST,
+			fmt.Sprintf("cannot transfer ownership to player %d", target))
+		return
+	}
+
+	room.setOwner(target)
+	room.broadcastRoomInfoChanged([]uint32{})
+}
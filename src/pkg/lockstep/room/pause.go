@@ -0,0 +1,280 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"log"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// pauseState 对局的暂停状态与暂停投票，仅在房间主循环中访问
+// 暂停期间帧时钟停止，不产生新的帧，也不更新房间的活跃时间
+type pauseState struct {
+	active bool
+	since  time.Time
+	// 发起或导致本次暂停的玩家
+	by     uint32
+	reason messages.PauseReason
+	// 本次暂停最多持续到的时间，由发起玩家剩余的暂停时长决定，不限时为零值
+	deadline time.Time
+	// 投票切换暂停状态的玩家，状态切换后清空
+	votes map[uint32]struct{}
+	// 发起本轮暂停投票的玩家，暂停的时长计入其暂停时长
+	initiator uint32
+}
+
+// pauseByVote 是否由在线玩家投票暂停与恢复，否则只有房主可以
+func (room *Room) pauseByVote() bool {
+	return room.LockstepConfig.PauseVotePercent == nil || *room.LockstepConfig.PauseVotePercent > 0
+}
+
+// PauseVoteQuorum 共有 online 名在线玩家时，暂停或恢复所需的票数，至少为 1
+func (room *Room) PauseVoteQuorum(online int) int {
+	percent := config.DefaultPauseVotePercent
+	if room.LockstepConfig.PauseVotePercent != nil {
+		percent = int(*room.LockstepConfig.PauseVotePercent)
+	}
+	return max(1, (online*percent+99)/100)
+}
+
+// PauseBudget 每名玩家每场对局可用的暂停时长，0 为不允许玩家暂停
+func (room *Room) PauseBudget() time.Duration {
+	if room.LockstepConfig.MaxPauseBudget == nil {
+		return config.DefaultMaxPauseBudget * time.Millisecond
+	}
+	return time.Duration(*room.LockstepConfig.MaxPauseBudget) * time.Millisecond
+}
+
+// AutoPauseOnDisconnect 玩家在对局中断线时是否自动暂停
+func (room *Room) AutoPauseOnDisconnect() bool {
+	if room.LockstepConfig.AutoPauseOnDisconnect == nil {
+		return config.DefaultAutoPauseOnDisconnect
+	}
+	return *room.LockstepConfig.AutoPauseOnDisconnect
+}
+
+// IsPaused 对局是否暂停中，可跨 goroutine 调用
+func (room *Room) IsPaused() bool {
+	return room.paused.Load()
+}
+
+// pauseRemaining 玩家在本场对局中剩余的暂停时长
+func (room *Room) pauseRemaining(c *client.Client) time.Duration {
+	return max(0, room.PauseBudget()-c.PauseUsed)
+}
+
+// handlePause 处理暂停请求
+func (room *Room) handlePause(from *client.Client, payload *messages.SessionRequest_Pause) {
+	if payload == nil || payload.Pause == nil {
+		return
+	}
+	if room.pause.active {
+		room.sendError(from, messages.ErrorCode_ERROR_CODE_INVALID_REQUEST, "game is already paused")
+		return
+	}
+	uid := from.GetID()
+	if !room.pauseByVote() {
+		if !room.IsOwner(uid) {
+			room.sendError(from, messages.ErrorCode_ERROR_CODE_PERMISSION_DENIED, "only the room owner can pause")
+			return
+		}
+		if room.checkPauseBudget(from) {
+			room.pauseGame(uid, messages.PauseReason_PAUSE_REASON_PLAYER_REQUEST)
+		}
+		return
+	}
+
+	if len(room.pause.votes) == 0 {
+		// 发起投票的玩家需要有剩余的暂停时长
+		if !room.checkPauseBudget(from) {
+			return
+		}
+		room.pause.initiator = uid
+	}
+	if room.votePause(uid) {
+		room.pauseGame(room.pause.initiator, messages.PauseReason_PAUSE_REASON_PLAYER_REQUEST)
+	}
+}
+
+// handleResume 处理恢复请求
+func (room *Room) handleResume(from *client.Client, payload *messages.SessionRequest_Resume) {
+	if payload == nil || payload.Resume == nil {
+		return
+	}
+	if !room.pause.active {
+		room.sendError(from, messages.ErrorCode_ERROR_CODE_INVALID_REQUEST, "game is not paused")
+		return
+	}
+	uid := from.GetID()
+	if !room.pauseByVote() {
+		if !room.IsOwner(uid) {
+			room.sendError(from, messages.ErrorCode_ERROR_CODE_PERMISSION_DENIED, "only the room owner can resume")
+			return
+		}
+		room.resumeGame(uid, messages.PauseReason_PAUSE_REASON_PLAYER_REQUEST)
+		return
+	}
+	if room.votePause(uid) {
+		room.resumeGame(uid, messages.PauseReason_PAUSE_REASON_PLAYER_REQUEST)
+	}
+}
+
+// checkPauseBudget 检查玩家是否还有剩余的暂停时长，没有时告知玩家
+func (room *Room) checkPauseBudget(c *client.Client) bool {
+	if room.pauseRemaining(c) > 0 {
+		return true
+	}
+	room.sendError(c, messages.ErrorCode_ERROR_CODE_PERMISSION_DENIED, "pause budget exhausted")
+	return false
+}
+
+// votePause 记录一名玩家切换暂停状态的投票，票数达到要求时返回 true
+// 票数不足时广播当前的投票进度
+func (room *Room) votePause(uid uint32) bool {
+	if room.pause.votes == nil {
+		room.pause.votes = make(map[uint32]struct{})
+	}
+	room.pause.votes[uid] = struct{}{}
+	votes, needed := room.pauseVotes()
+	if len(votes) >= needed {
+		return true
+	}
+	room.broadcastPauseState(uid, messages.PauseReason_PAUSE_REASON_PLAYER_REQUEST)
+	return false
+}
+
+// pauseVotes 在线玩家中已投票的玩家与所需票数，断线或离开的玩家的投票不计入
+func (room *Room) pauseVotes() ([]uint32, int) {
+	votes := make([]uint32, 0, len(room.pause.votes))
+	online := 0
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value == nil || value.IsDisconnected() {
+			return true
+		}
+		online++
+		if _, ok := room.pause.votes[key]; ok {
+			votes = append(votes, key)
+		}
+		return true
+	})
+	slices.Sort(votes)
+	return votes, room.PauseVoteQuorum(online)
+}
+
+// pauseGame 暂停对局并停止帧时钟
+// 玩家发起的暂停最多持续到其剩余的暂停时长用尽
+func (room *Room) pauseGame(by uint32, reason messages.PauseReason) {
+	now := time.Now()
+	room.pause = pauseState{active: true, since: now, by: by, reason: reason}
+	if reason == messages.PauseReason_PAUSE_REASON_PLAYER_REQUEST {
+		if c, ok := room.ClientsContainer.Clients.Load(by); ok {
+			room.pause.deadline = now.Add(room.pauseRemaining(c))
+		}
+	}
+	room.paused.Store(true)
+	room.stopFrameClock()
+	room.broadcastPauseState(by, reason)
+	log.Printf("⏸️ Room %d paused at frame %d by player %d (reason: %v)",
+		room.ID, room.SyncData.NextFrameID.Load()-1, by, reason)
+}
+
+// resumeGame 恢复对局并重新启动帧时钟
+// 玩家发起的暂停所持续的时长计入发起玩家的暂停时长
+func (room *Room) resumeGame(by uint32, reason messages.PauseReason) {
+	elapsed := time.Since(room.pause.since)
+	if room.pause.reason == messages.PauseReason_PAUSE_REASON_PLAYER_REQUEST {
+		if c, ok := room.ClientsContainer.Clients.Load(room.pause.by); ok {
+			c.PauseUsed += elapsed
+		}
+	}
+	room.pause = pauseState{}
+	room.paused.Store(false)
+
+	// 暂停期间的等待不计入悲观锁步的输入超时
+	room.frameWaitStart = time.Time{}
+	room.touchActiveTime()
+	room.startFrameClock()
+	room.broadcastPauseState(by, reason)
+	log.Printf("▶️ Room %d resumed at frame %d by player %d after %v (reason: %v)",
+		room.ID, room.SyncData.NextFrameID.Load()-1, by, elapsed.Truncate(time.Millisecond), reason)
+}
+
+// clearPause 离开 InGame 阶段时丢弃暂停状态，帧时钟由 changeStage 停止
+func (room *Room) clearPause() {
+	room.pause = pauseState{}
+	room.paused.Store(false)
+}
+
+// pauseOnDisconnect 玩家在对局中断线时自动暂停
+// 已暂停时保持原有的暂停，恢复后不再因这名玩家自动暂停
+func (room *Room) pauseOnDisconnect(player *client.Client) {
+	if !room.AutoPauseOnDisconnect() || room.IsReplay() || room.pause.active ||
+		!room.RoomStage.EqualTo(constants.STAGE_InGame) {
+		return
+	}
+	room.pauseGame(player.GetID(), messages.PauseReason_PAUSE_REASON_PLAYER_DISCONNECTED)
+}
+
+// resumeAfterDisconnect 因断线自动暂停的对局，在断线的玩家均已重连或离开后自动恢复
+// 在玩家重连后以及周期性维护中调用
+func (room *Room) resumeAfterDisconnect() {
+	if !room.pause.active || room.pause.reason != messages.PauseReason_PAUSE_REASON_PLAYER_DISCONNECTED {
+		return
+	}
+	disconnected := false
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		disconnected = value != nil && value.IsDisconnected()
+		return !disconnected
+	})
+	if !disconnected {
+		room.resumeGame(0, messages.PauseReason_PAUSE_REASON_DISCONNECT_RESOLVED)
+	}
+}
+
+// expirePause 发起暂停的玩家的暂停时长用尽时自动恢复
+// 在周期性维护中调用，精度为维护间隔
+func (room *Room) expirePause() {
+	if !room.pause.active || room.pause.deadline.IsZero() || time.Now().Before(room.pause.deadline) {
+		return
+	}
+	log.Printf("⏸️ Room %d pause budget of player %d exhausted", room.ID, room.pause.by)
+	room.resumeGame(0, messages.PauseReason_PAUSE_REASON_BUDGET_EXHAUSTED)
+}
+
+// pauseStateMessage 制作当前的暂停状态，by 与 reason 为本次变化的发起者与原因
+func (room *Room) pauseStateMessage(by uint32, reason messages.PauseReason) *messages.SessionResponse {
+	state := &messages.ResponsePaused{
+		Paused:   room.pause.active,
+		FrameId:  room.SyncData.NextFrameID.Load() - 1,
+		PlayerId: by,
+		Reason:   reason,
+	}
+	if room.pauseByVote() {
+		votes, needed := room.pauseVotes()
+		state.Votes, state.VotesNeeded = votes, uint32(needed)
+	}
+	if room.pause.active && !room.pause.deadline.IsZero() {
+		state.RemainingMs = uint32(max(0, time.Until(room.pause.deadline)).Milliseconds())
+	}
+	return &messages.SessionResponse{Payload: &messages.SessionResponse_Paused{Paused: state}}
+}
+
+// broadcastPauseState 向所有玩家与观战者广播暂停状态
+func (room *Room) broadcastPauseState(by uint32, reason messages.PauseReason) {
+	room.broadcastWithSpectators(room.pauseStateMessage(by, reason), []uint32{})
+}
+
+// sendPauseState 向暂停中加入或重连的客户端发送暂停状态
+func (room *Room) sendPauseState(c *client.Client) {
+	if !room.pause.active {
+		return
+	}
+	if b, err := proto.Marshal(room.pauseStateMessage(room.pause.by, room.pause.reason)); err == nil {
+		c.WriteReliable(b)
+	}
+}
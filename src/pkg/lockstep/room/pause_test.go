@@ -0,0 +1,154 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	lockstep_sync "lockstep-core/src/pkg/lockstep/sync"
+	"testing"
+	"time"
+)
+
+// newPauseTestRoom 创建处于 InGame 阶段、有 players 名玩家的房间
+func newPauseTestRoom(t *testing.T, players int, configure func(*config.LockstepConfig)) (*Room, []*client.Client) {
+	t.Helper()
+	room := newTestRoom(t, configure)
+	room.RoomStage.Store(constants.STAGE_InGame)
+	clients := make([]*client.Client, 0, players)
+	for uid := uint32(1); uid <= uint32(players); uid++ {
+		clients = append(clients, addTestPlayer(t, room, uid))
+	}
+	return room, clients
+}
+
+func pauseRequest() *messages.SessionRequest_Pause {
+	return &messages.SessionRequest_Pause{Pause: &messages.RequestPause{}}
+}
+
+func resumeRequest() *messages.SessionRequest_Resume {
+	return &messages.SessionRequest_Resume{Resume: &messages.RequestResume{}}
+}
+
+func TestPauseVoteQuorum(t *testing.T) {
+	cases := []struct {
+		percent uint16
+		online  int
+		want    int
+	}{
+		{50, 4, 2},
+		{50, 3, 2},
+		{50, 1, 1},
+		{50, 0, 1},
+		{100, 3, 3},
+		{1, 10, 1},
+	}
+	for _, tc := range cases {
+		room := newTestRoom(t, func(cfg *config.LockstepConfig) {
+			cfg.PauseVotePercent = config.Uint16Ptr(tc.percent)
+		})
+		if got := room.PauseVoteQuorum(tc.online); got != tc.want {
+			t.Errorf("PauseVoteQuorum(%d) at %d%% = %d, want %d", tc.online, tc.percent, got, tc.want)
+		}
+	}
+}
+
+func TestPauseAndResumeByVote(t *testing.T) {
+	room, players := newPauseTestRoom(t, 3, nil)
+
+	room.handlePause(players[0], pauseRequest())
+	room.handlePause(players[0], pauseRequest())
+	if room.IsPaused() {
+		t.Fatal("a single vote paused the game")
+	}
+	room.handlePause(players[1], pauseRequest())
+	if !room.IsPaused() || room.pause.by != 1 || room.pause.deadline.IsZero() {
+		t.Fatalf("game not paused by the initiator after quorum: %+v", room.pause)
+	}
+
+	room.pause.since = time.Now().Add(-2 * time.Second)
+	room.handleResume(players[2], resumeRequest())
+	if !room.IsPaused() {
+		t.Fatal("a single vote resumed the game")
+	}
+	room.handleResume(players[1], resumeRequest())
+	if room.IsPaused() {
+		t.Fatal("game not resumed after quorum")
+	}
+	if players[0].PauseUsed < 2*time.Second || players[1].PauseUsed != 0 {
+		t.Fatalf("pause time charged %v to the initiator and %v to the second voter, want only the initiator charged",
+			players[0].PauseUsed, players[1].PauseUsed)
+	}
+}
+
+func TestPauseVotesIgnoreDisconnectedPlayers(t *testing.T) {
+	room, players := newPauseTestRoom(t, 4, nil)
+	players[1].SetState(lockstep_sync.PlayerStateDisconnected)
+	players[2].SetState(lockstep_sync.PlayerStateDisconnected)
+
+	if _, needed := room.pauseVotes(); needed != 1 {
+		t.Fatalf("votes needed = %d, want 1 of 2 online players", needed)
+	}
+	room.handlePause(players[0], pauseRequest())
+	if !room.IsPaused() {
+		t.Fatal("one of two online players did not reach the quorum")
+	}
+}
+
+func TestPauseBudgetExhausted(t *testing.T) {
+	room, players := newPauseTestRoom(t, 1, nil)
+	players[0].PauseUsed = room.PauseBudget()
+
+	room.handlePause(players[0], pauseRequest())
+	if room.IsPaused() || len(room.pause.votes) != 0 {
+		t.Fatalf("player without pause budget paused the game or started a vote: %+v", room.pause)
+	}
+}
+
+func TestExpirePauseChargesBudget(t *testing.T) {
+	room, players := newPauseTestRoom(t, 1, func(cfg *config.LockstepConfig) {
+		cfg.MaxPauseBudget = config.Uint32Ptr(1000)
+	})
+	players[0].PauseUsed = 400 * time.Millisecond
+
+	room.handlePause(players[0], pauseRequest())
+	if !room.IsPaused() {
+		t.Fatal("game not paused")
+	}
+	if remaining := time.Until(room.pause.deadline); remaining > 600*time.Millisecond {
+		t.Fatalf("pause may last %v, want at most the remaining 600ms", remaining)
+	}
+
+	room.pause.since = time.Now().Add(-600 * time.Millisecond)
+	room.pause.deadline = time.Now().Add(-time.Millisecond)
+	room.expirePause()
+	if room.IsPaused() {
+		t.Fatal("pause did not expire after the budget was used up")
+	}
+	if room.pauseRemaining(players[0]) != 0 {
+		t.Fatalf("remaining pause budget = %v, want 0", room.pauseRemaining(players[0]))
+	}
+}
+
+func TestOwnerOnlyPause(t *testing.T) {
+	room, players := newPauseTestRoom(t, 2, func(cfg *config.LockstepConfig) {
+		cfg.PauseVotePercent = config.Uint16Ptr(0)
+	})
+
+	room.handlePause(players[1], pauseRequest())
+	if room.IsPaused() {
+		t.Fatal("a player other than the owner paused the game")
+	}
+	room.handlePause(players[0], pauseRequest())
+	if !room.IsPaused() {
+		t.Fatal("the owner could not pause the game")
+	}
+	room.handleResume(players[1], resumeRequest())
+	if !room.IsPaused() {
+		t.Fatal("a player other than the owner resumed the game")
+	}
+	room.handleResume(players[0], resumeRequest())
+	if room.IsPaused() {
+		t.Fatal("the owner could not resume the game")
+	}
+}
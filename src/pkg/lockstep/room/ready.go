@@ -0,0 +1,133 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+	"slices"
+	"time"
+)
+
+// ReadyCountdownDuration 准备倒计时的时长，0 为不倒计时
+func (room *Room) ReadyCountdownDuration() time.Duration {
+	if room.LockstepConfig.ReadyCountdown == nil {
+		return config.DefaultReadyCountdown * time.Millisecond
+	}
+	return time.Duration(*room.LockstepConfig.ReadyCountdown) * time.Millisecond
+}
+
+// ReadyQuorum 共有 total 名玩家时，开始准备倒计时所需的准备人数，至少为 1
+func (room *Room) ReadyQuorum(total int) int {
+	percent := config.DefaultReadyQuorumPercent
+	if room.LockstepConfig.ReadyQuorumPercent != nil {
+		percent = int(*room.LockstepConfig.ReadyQuorumPercent)
+	}
+	return max(1, (total*percent+99)/100)
+}
+
+// onReadyChanged 玩家的准备状态变化后调用
+// 广播准备人数；所有玩家均已准备时进入 Loading 阶段，否则按准备人数开始或取消倒计时
+func (room *Room) onReadyChanged() {
+	readyPlayerIds := make([]uint32, 0)
+	playerCount := 0
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value == nil {
+			return true
+		}
+		if value.IsReady {
+			readyPlayerIds = append(readyPlayerIds, key)
+		}
+		playerCount++
+		return true
+	})
+	// 广播准备状态变更
+	innerReadyResp := &messages.ResponseReadyCountUpdate{
+		ReadyPlayerIds: readyPlayerIds,
+		TotalCount:     uint32(playerCount),
+	}
+	sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_ReadyCountUpdate{ReadyCountUpdate: innerReadyResp}}
+	room.broadcastWithSpectators(sresp, []uint32{})
+
+	if playerCount > 0 && playerCount == len(readyPlayerIds) {
+		// 所有玩家均已准备好
+		data := room.Game.OnHandleAllReady()
+		room.changeStage(constants.STAGE_Loading, data)
+		return
+	}
+	room.updateReadyCountdown(len(readyPlayerIds), playerCount)
+}
+
+// updateReadyCountdown 准备人数达到法定人数时开始倒计时，低于法定人数时取消
+func (room *Room) updateReadyCountdown(ready, total int) {
+	countdown := room.ReadyCountdownDuration()
+	if countdown <= 0 {
+		return
+	}
+	running := !room.readyDeadline.IsZero()
+	quorum := ready >= room.ReadyQuorum(total)
+	switch {
+	case quorum && !running:
+		room.readyDeadline = time.Now().Add(countdown)
+		room.broadcastReadyCountdown(true, countdown)
+		log.Printf("⏳ Room %d ready countdown started (%d/%d ready, %v)", room.ID, ready, total, countdown)
+	case !quorum && running:
+		room.readyDeadline = time.Time{}
+		room.broadcastReadyCountdown(false, 0)
+		log.Printf("⏳ Room %d ready countdown cancelled (%d/%d ready)", room.ID, ready, total)
+	}
+}
+
+// broadcastReadyCountdown 广播准备倒计时的开始或取消
+func (room *Room) broadcastReadyCountdown(active bool, remaining time.Duration) {
+	resp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_ReadyCountdown{
+			ReadyCountdown: &messages.ResponseReadyCountdown{
+				Active:      active,
+				RemainingMs: uint32(remaining.Milliseconds()),
+			},
+		},
+	}
+	room.broadcastWithSpectators(resp, []uint32{})
+}
+
+// expireReadyCountdown 准备倒计时结束时，按游戏世界的决定自动准备或踢出仍未准备的玩家
+// 在周期性维护中调用，精度为维护间隔
+func (room *Room) expireReadyCountdown() {
+	if room.readyDeadline.IsZero() || time.Now().Before(room.readyDeadline) {
+		return
+	}
+	room.readyDeadline = time.Time{}
+	if !room.RoomStage.EqualTo(constants.STAGE_Preparing) || room.Game == nil {
+		return
+	}
+
+	unready := make([]uint32, 0)
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value != nil && !value.IsReady {
+			unready = append(unready, key)
+		}
+		return true
+	})
+	slices.Sort(unready)
+
+	if len(unready) > 0 {
+		switch room.Game.OnReadyTimeout(unready) {
+		case world.ReadyTimeoutKick:
+			log.Printf("⏳ Room %d ready countdown expired, kicking unready players %v", room.ID, unready)
+			for _, uid := range unready {
+				room.kickPlayer(uid, messages.CloseReason_CLOSE_REASON_KICKED, "not ready in time")
+			}
+		default:
+			log.Printf("⏳ Room %d ready countdown expired, auto-readying players %v", room.ID, unready)
+			for _, uid := range unready {
+				if c, ok := room.ClientsContainer.Clients.Load(uid); ok {
+					c.IsReady = true
+				}
+			}
+		}
+	}
+	room.onReadyChanged()
+}
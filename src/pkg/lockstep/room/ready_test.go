@@ -0,0 +1,22 @@
+package room
+
+import (
+	"lockstep-core/src/constants"
+	"lockstep-core/src/internal/defaults"
+	"testing"
+)
+
+func TestOnReadyChangedSkipsNilSlots(t *testing.T) {
+	room := newTestRoom(t, nil)
+	room.Game = &defaults.DefaultGameWorld{}
+	room.RoomStage.Store(constants.STAGE_Preparing)
+	player := addTestPlayer(t, room, 1)
+	room.ClientsContainer.Clients.Store(2, nil)
+
+	player.IsReady = true
+	room.onReadyChanged()
+
+	if !room.RoomStage.EqualTo(constants.STAGE_Loading) {
+		t.Fatalf("stage = %#x, want Loading once every player is ready", uint32(room.RoomStage.Load()))
+	}
+}
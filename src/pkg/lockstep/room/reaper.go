@@ -0,0 +1,87 @@
+package room
+
+import (
+	"fmt"
+	"lockstep-core/src/config"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"log"
+	"time"
+)
+
+// seconds 将以秒为单位的配置项转换为时长，未配置时使用默认值 def
+func seconds(v *uint32, def uint32) time.Duration {
+	if v == nil {
+		return time.Duration(def) * time.Second
+	}
+	return time.Duration(*v) * time.Second
+}
+
+// idleTimeoutOf 获取指定阶段的房间空闲超时，0 为不回收
+func (rm *RoomManager) idleTimeoutOf(stage constants.Stage) time.Duration {
+	switch stage {
+	case constants.STAGE_InLobby:
+		return seconds(rm.ReaperConfig.LobbyIdleTimeout, config.DefaultLobbyIdleTimeout)
+	case constants.STAGE_Preparing:
+		return seconds(rm.ReaperConfig.PreparingIdleTimeout, config.DefaultPreparingIdleTimeout)
+	case constants.STAGE_Loading:
+		return seconds(rm.ReaperConfig.LoadingIdleTimeout, config.DefaultLoadingIdleTimeout)
+	case constants.STAGE_InGame:
+		return seconds(rm.ReaperConfig.InGameIdleTimeout, config.DefaultInGameIdleTimeout)
+	case constants.STAGE_PostGame:
+		return seconds(rm.ReaperConfig.PostGameIdleTimeout, config.DefaultPostGameIdleTimeout)
+	default:
+		return 0
+	}
+}
+
+// runReaper 周期性扫描并回收空闲房间
+func (rm *RoomManager) runReaper() {
+	interval := seconds(rm.ReaperConfig.ReapInterval, config.DefaultReapInterval)
+	if interval == 0 {
+		log.Printf("🧹 Room reaper disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			rm.reapIdleRooms()
+		case <-rm.reaperStop:
+			return
+		}
+	}
+}
+
+// reapIdleRooms 扫描一次所有房间，请求关闭空房间与空闲超时的房间
+func (rm *RoomManager) reapIdleRooms() {
+	for _, r := range rm.snapshotRooms() {
+		if reason, ok := rm.shouldReap(r); ok {
+			log.Printf("🧹 Reaping room %d: %s", r.ID, reason)
+			r.RequestClose(messages.CloseReason_CLOSE_REASON_IDLE, reason)
+		}
+	}
+}
+
+// shouldReap 判断房间是否需要被回收，并给出原因
+func (rm *RoomManager) shouldReap(r *Room) (string, bool) {
+	stage := r.RoomStage.Load()
+	if stage == constants.STAGE_CLOSED {
+		return "", false
+	}
+	idle := r.IdleDuration()
+
+	if emptyTimeout := seconds(rm.ReaperConfig.EmptyRoomTimeout, config.DefaultEmptyRoomTimeout); emptyTimeout > 0 &&
+		r.GetActivePlayerCount() == 0 && idle >= emptyTimeout {
+		return fmt.Sprintf("room empty for %v", idle.Truncate(time.Second)), true
+	}
+
+	// 暂停中不产生新的帧，房间的活跃时间不再更新
+	// 暂停的时长有限，因此暂停中不按阶段的空闲超时回收，空房间仍照常回收
+	if idleTimeout := rm.idleTimeoutOf(stage); idleTimeout > 0 && idle >= idleTimeout && !r.IsPaused() {
+		return fmt.Sprintf("room idle for %v in stage 0x%x", idle.Truncate(time.Second), uint32(stage)), true
+	}
+	return "", false
+}
@@ -0,0 +1,64 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/pkg/lockstep/client"
+	lockstep_sync "lockstep-core/src/pkg/lockstep/sync"
+	"log"
+	"time"
+)
+
+// ReconnectGracePeriodDuration 断线玩家保留席位等待重连的时间，0 为不等待
+func (room *Room) ReconnectGracePeriodDuration() time.Duration {
+	if room.LockstepConfig.ReconnectGracePeriod == nil {
+		return config.DefaultReconnectGracePeriod * time.Millisecond
+	}
+	return time.Duration(*room.LockstepConfig.ReconnectGracePeriod) * time.Millisecond
+}
+
+// markDisconnected 将玩家置为断线等待重连状态
+// 玩家仍保留在房间中，其准备、加载与帧同步状态不变
+func (room *Room) markDisconnected(player *client.Client) {
+	player.SetState(lockstep_sync.PlayerStateDisconnected)
+	player.DisconnectedAt = time.Now()
+
+	// 经由出站队列关闭会话，排队的消息已无意义，重连换入新会话后出站队列恢复服务
+	player.Close()
+	log.Printf("🟠 Player %d disconnected from room %d, awaiting reconnect for %v",
+		player.GetID(), room.ID, room.ReconnectGracePeriodDuration())
+	room.pauseOnDisconnect(player)
+}
+
+// resumePlayer 将重连的新会话换入玩家原有的 Client
+// 若玩家仍在线，则视为重复登录，旧会话被顶替
+// 返回 false 表示该玩家已不在房间中
+func (room *Room) resumePlayer(reconnecting *client.Client) (*client.Client, bool) {
+	existing, ok := room.ClientsContainer.Clients.Load(reconnecting.GetID())
+	if !ok || existing == nil {
+		return nil, false
+	}
+	if !existing.IsDisconnected() {
+		room.takeOverSession(existing, reconnecting)
+		return existing, true
+	}
+	existing.SwapSession(reconnecting.Session)
+	log.Printf("🟢 Player %d reconnected to room %d after %v",
+		existing.GetID(), room.ID, time.Since(existing.DisconnectedAt))
+	return existing, true
+}
+
+// expireDisconnectedPlayers 移除等待重连超时的玩家
+func (room *Room) expireDisconnectedPlayers() {
+	grace := room.ReconnectGracePeriodDuration()
+	expired := make([]*client.Client, 0)
+	room.ClientsContainer.Clients.Range(func(key uint32, value *client.Client) bool {
+		if value != nil && value.IsDisconnected() && time.Since(value.DisconnectedAt) >= grace {
+			expired = append(expired, value)
+		}
+		return true
+	})
+	for _, player := range expired {
+		log.Printf("🟡 Player %d reconnect grace period expired in room %d", player.GetID(), room.ID)
+		room.removePlayer(player)
+	}
+}
@@ -0,0 +1,49 @@
+package room
+
+import (
+	"lockstep-core/src/pkg/lockstep/client"
+	"testing"
+	"time"
+)
+
+func TestMarkDisconnectedClosesThroughOutbox(t *testing.T) {
+	room := newTestRoom(t, nil)
+	player := addTestPlayer(t, room, 1)
+	old := player.GetSession().(*fakeSession)
+
+	room.markDisconnected(player)
+	if old.IsConnected() {
+		t.Fatal("session still connected after disconnect")
+	}
+	if !player.IsDisconnected() {
+		t.Fatal("player not marked as disconnected")
+	}
+
+	// 断线期间写入的消息被丢弃，重连后出站队列服务新会话
+	player.WriteReliable([]byte("dropped"))
+	fresh := &fakeSession{connected: true}
+	room.resumePlayer(client.NewClient(player.GetID(), fresh, nil))
+	player.WriteReliable([]byte("hello"))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		fresh.mu.Lock()
+		sent := len(fresh.sent)
+		fresh.mu.Unlock()
+		if sent > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("message not delivered to the reconnected session")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	fresh.mu.Lock()
+	defer fresh.mu.Unlock()
+	if len(fresh.sent) != 1 || string(fresh.sent[0]) != "hello" {
+		t.Fatalf("sent = %q, want [hello]", fresh.sent)
+	}
+	if len(old.sent) != 0 {
+		t.Fatalf("old session received %q", old.sent)
+	}
+}
@@ -0,0 +1,107 @@
+package room
+
+import (
+	"errors"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/replay"
+	"lockstep-core/src/pkg/lockstep/world"
+	"log"
+)
+
+// ReplayDirPath 回放文件的保存目录，为空则不录制
+func (room *Room) ReplayDirPath() string {
+	if room.LockstepConfig.ReplayDir == nil {
+		return ""
+	}
+	return *room.LockstepConfig.ReplayDir
+}
+
+// replayHeader 制作本房间当前对局的回放文件头
+func (room *Room) replayHeader() *messages.ReplayHeader {
+	header := &messages.ReplayHeader{
+		RoomId:          room.ID,
+		RoomName:        room.Name,
+		PlayerIds:       room.Clients.ToSlice(),
+		FrameIntervalMs: uint32(room.FrameInterval().Milliseconds()),
+	}
+	if metadata := room.metadata.Load(); metadata != nil {
+		header.Metadata = *metadata
+	}
+	return header
+}
+
+// startRecording 对局开始时创建回放录制，此后所有保存的帧与快照都会写入回放文件
+// 未配置回放目录时不录制
+func (room *Room) startRecording(data []byte) {
+	room.recordingPath = ""
+	dir := room.ReplayDirPath()
+	if dir == "" {
+		return
+	}
+
+	rec, err := replay.NewRecorder(dir, room.replayHeader())
+	if err != nil {
+		log.Printf("🔴 Room %d failed to start replay recording: %v", room.ID, err)
+		return
+	}
+
+	room.recorder = rec
+	room.recordingPath = rec.Path()
+	room.SyncData.SetSink(rec)
+	rec.RecordStageChange(0, constants.STAGE_InGame, data)
+}
+
+// stopRecording 对局结束或房间销毁时记录最后的阶段变更并关闭回放文件
+// 回放文件路径保留到下一场对局开始，对局结束后仍可 DumpFrameHistory
+func (room *Room) stopRecording(newStage constants.Stage, data []byte) {
+	if room.recorder == nil {
+		return
+	}
+	room.recorder.RecordStageChange(room.SyncData.NextFrameID.Load()-1, newStage, data)
+	room.SyncData.SetSink(nil)
+	if err := room.recorder.Close(); err != nil {
+		log.Printf("🔴 Room %d replay %s is incomplete: %v", room.ID, room.recorder.Path(), err)
+	}
+	room.recorder = nil
+}
+
+// DumpFrameHistory 将当前或上一场对局的帧与快照写入 dir 下的回放文件，返回文件路径
+// 配置了回放目录时复制对局的持续录制，包含从开始至今的所有帧；
+// 否则写入内存中尚未淘汰的帧历史与快照；可在对局结束的回调中调用
+// 仅在房间主循环中调用
+func (room *Room) DumpFrameHistory(dir string) (string, error) {
+	if room.recordingPath == "" {
+		return room.dumpSyncHistory(dir)
+	}
+	if room.recorder != nil {
+		if err := room.recorder.Flush(); err != nil {
+			return "", err
+		}
+	}
+	return replay.CopyReplay(room.recordingPath, dir)
+}
+
+// dumpSyncHistory 将 ServerSyncData 中保留的帧与快照写入 dir 下的回放文件
+func (room *Room) dumpSyncHistory(dir string) (string, error) {
+	frames := room.SyncData.FrameDatas
+	if frames.Len() == 0 {
+		return "", errors.New("no match has been played in this room")
+	}
+
+	rec, err := replay.NewRecorder(dir, room.replayHeader())
+	if err != nil {
+		return "", err
+	}
+	oldest, newest := frames.Range()
+	for frameID := oldest; frameID <= newest; frameID++ {
+		if frame, ok := frames.Get(frameID); ok {
+			rec.OnStoreFrame(frameID, frame)
+		}
+	}
+	room.SyncData.Snapshots.Range(func(frameID uint32, snapshot world.Snapshot) bool {
+		rec.OnStoreSnapshot(frameID, snapshot)
+		return true
+	})
+	return rec.Path(), rec.Close()
+}
@@ -0,0 +1,118 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/pkg/lockstep/replay"
+	"lockstep-core/src/pkg/lockstep/world"
+	"testing"
+)
+
+func TestDumpFrameHistoryKeepsPrunedFrames(t *testing.T) {
+	room := newTestRoom(t, func(c *config.LockstepConfig) {
+		c.FrameHistorySize = config.Uint32Ptr(16)
+		c.ReplayDir = config.StringPtr(t.TempDir())
+	})
+	room.startRecording(nil)
+
+	const lastFrame = 100
+	for frameID := uint32(1); frameID <= lastFrame; frameID++ {
+		room.SyncData.StoreFrame(frameID, &world.FrameData{FrameId: frameID})
+		room.SyncData.NextFrameID.Store(frameID + 1)
+		// 与 stepGameTick 一样按 ack 淘汰历史帧
+		room.SyncData.Prune(frameID - 1)
+	}
+	if oldest, _ := room.SyncData.FrameDatas.Range(); oldest <= 1 {
+		t.Fatalf("frame history was not pruned, oldest frame %d", oldest)
+	}
+
+	path, err := room.DumpFrameHistory(t.TempDir())
+	if err != nil {
+		t.Fatalf("DumpFrameHistory: %v", err)
+	}
+	source, err := replay.LoadReplay(path)
+	if err != nil {
+		t.Fatalf("LoadReplay: %v", err)
+	}
+	if len(source.Frames) != lastFrame || source.LastFrameID() != lastFrame {
+		t.Fatalf("dump holds %d frames up to %d, want %d", len(source.Frames), source.LastFrameID(), lastFrame)
+	}
+
+	player := newTestRoom(t, nil)
+	player.replay = newReplayPlayback(source)
+	player.startReplay()
+	if next := player.SyncData.NextFrameID.Load(); next != 1 {
+		t.Fatalf("playback starts at frame %d, want 1", next)
+	}
+	if rewound := player.seekReplay(80); rewound {
+		t.Fatalf("seeking forward reported a rewind")
+	}
+	if current := player.SyncData.NextFrameID.Load() - 1; current != 80 {
+		t.Fatalf("seek forward reached frame %d, want 80", current)
+	}
+	if rewound := player.seekReplay(10); !rewound {
+		t.Fatalf("seeking backward did not report a rewind")
+	}
+	if current := player.SyncData.NextFrameID.Load() - 1; current != 10 {
+		t.Fatalf("seek backward reached frame %d, want 10", current)
+	}
+}
+
+func TestDumpFrameHistoryAfterMatchEnd(t *testing.T) {
+	room := newTestRoom(t, func(c *config.LockstepConfig) {
+		c.ReplayDir = config.StringPtr(t.TempDir())
+	})
+	if _, err := room.DumpFrameHistory(t.TempDir()); err == nil {
+		t.Fatalf("dump before any match should fail")
+	}
+
+	room.startRecording(nil)
+	room.SyncData.StoreFrame(1, &world.FrameData{FrameId: 1})
+	room.SyncData.NextFrameID.Store(2)
+	room.stopRecording(constants.STAGE_PostGame, nil)
+
+	path, err := room.DumpFrameHistory(t.TempDir())
+	if err != nil {
+		t.Fatalf("DumpFrameHistory: %v", err)
+	}
+	if source, err := replay.LoadReplay(path); err != nil || source.LastFrameID() != 1 {
+		t.Fatalf("LoadReplay: %v", err)
+	}
+}
+
+func TestDumpFrameHistoryWithoutReplayDir(t *testing.T) {
+	room := newTestRoom(t, func(c *config.LockstepConfig) {
+		c.FrameHistorySize = config.Uint32Ptr(16)
+	})
+	room.startRecording(nil)
+	if room.recorder != nil {
+		t.Fatalf("match recorded without a replay dir")
+	}
+
+	const lastFrame = 40
+	for frameID := uint32(1); frameID <= lastFrame; frameID++ {
+		room.SyncData.StoreFrame(frameID, &world.FrameData{FrameId: frameID})
+		if frameID%10 == 0 {
+			room.SyncData.StoreSnapshot(frameID, world.Snapshot{byte(frameID)})
+		}
+		room.SyncData.NextFrameID.Store(frameID + 1)
+		room.SyncData.Prune(frameID - 1)
+	}
+	room.stopRecording(constants.STAGE_PostGame, nil)
+
+	path, err := room.DumpFrameHistory(t.TempDir())
+	if err != nil {
+		t.Fatalf("DumpFrameHistory: %v", err)
+	}
+	source, err := replay.LoadReplay(path)
+	if err != nil {
+		t.Fatalf("LoadReplay: %v", err)
+	}
+	oldest, newest := room.SyncData.FrameDatas.Range()
+	if len(source.Frames) != int(newest-oldest+1) || source.LastFrameID() != lastFrame {
+		t.Fatalf("dump holds %d frames up to %d, want frames %d-%d", len(source.Frames), source.LastFrameID(), oldest, newest)
+	}
+	if len(source.Snapshots) != room.SyncData.Snapshots.Len() {
+		t.Fatalf("dump holds %d snapshots, want %d", len(source.Snapshots), room.SyncData.Snapshots.Len())
+	}
+}
@@ -0,0 +1,224 @@
+package room
+
+import (
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"lockstep-core/src/pkg/lockstep/replay"
+	"log"
+	"math"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// maxReplaySpeed 回放允许的最大播放速度倍率
+const maxReplaySpeed = 16
+
+// replayPlayback 回放房间的播放状态，仅在房间主循环中访问
+type replayPlayback struct {
+	source *replay.Replay
+	paused bool
+	// 播放速度倍率
+	speed float32
+	// 按倍率累计的待播放帧数，不足一帧的部分留到下一次帧时钟
+	progress float64
+}
+
+func newReplayPlayback(source *replay.Replay) *replayPlayback {
+	return &replayPlayback{
+		source: source,
+		speed:  1,
+	}
+}
+
+// IsReplay 是否为播放回放的只读房间
+func (room *Room) IsReplay() bool {
+	return room.replay != nil
+}
+
+// startReplay 回放房间不经过大厅与准备阶段，直接进入 InGame 并启动帧时钟
+// 不会调用游戏世界的 OnGameStart，也不会再次录制
+func (room *Room) startReplay() {
+	room.RoomStage.Store(constants.STAGE_InGame)
+	room.rewindReplay()
+	room.startFrameClock()
+	log.Printf("📼 Room %d is playing replay of room %d (%d frames)",
+		room.ID, room.replay.source.Header.GetRoomId(), room.replay.source.LastFrameID())
+}
+
+// stepReplayTick 回放房间的帧时钟，按播放速度从回放中取帧代替游戏世界的 GetFrameData
+// 没有观众时不推进，暂停时仍向客户端发送其缺少的帧
+func (room *Room) stepReplayTick() {
+	if room.ClientsContainer.GetPlayerCount() == 0 && room.Spectators.GetPlayerCount() == 0 {
+		return
+	}
+
+	pb := room.replay
+	if !pb.paused {
+		room.touchActiveTime()
+		pb.progress += float64(pb.speed)
+		for pb.progress >= 1 {
+			pb.progress--
+			if !room.feedReplayFrame() {
+				// 播放到结尾后自动暂停，可跳转后继续播放
+				pb.paused = true
+				pb.progress = 0
+				room.broadcastReplayState(false)
+				log.Printf("📼 Replay in room %d reached the end", room.ID)
+				break
+			}
+		}
+	}
+
+	current := room.SyncData.NextFrameID.Load() - 1
+	room.sendSpectatorFrames(current)
+	room.broadcastFrames(current, room.oldestPlayerAck(current))
+}
+
+// rewindReplay 清空帧历史并回到回放的起点
+// 回放不是从第 1 帧开始时，先载入起点前的快照，客户端通过快照追帧后继续步进
+func (room *Room) rewindReplay() {
+	room.SyncData.Reset()
+	start, snapshot := room.replay.source.Start()
+	if snapshot != nil {
+		room.SyncData.StoreSnapshot(snapshot.GetFrameId(), snapshot.GetData())
+	}
+	room.SyncData.NextFrameID.Store(start)
+}
+
+// feedReplayFrame 将回放中的下一帧及其快照存入帧历史，回放结束时返回 false
+// 回放房间不淘汰历史帧，迟到加入的观众可以从回放的起点或快照开始追帧
+func (room *Room) feedReplayFrame() bool {
+	frameID := room.SyncData.NextFrameID.Load()
+	frame, ok := room.replay.source.FrameAt(frameID)
+	if !ok {
+		return false
+	}
+	room.SyncData.StoreFrame(frameID, frame)
+	if snapshot, ok := room.replay.source.SnapshotAt(frameID); ok {
+		room.SyncData.StoreSnapshot(frameID, snapshot)
+	}
+	room.SyncData.NextFrameID.Add(1)
+	return true
+}
+
+// seekReplay 跳转到 target 帧，返回是否为回退
+// 跳转到之后的帧时直接快进；回退时清空帧历史并重置所有客户端的 ack，
+// 客户端收到 rewound 的 ResponseReplayState 后从回放的起点或快照重新步进
+func (room *Room) seekReplay(target uint32) bool {
+	if last := room.replay.source.LastFrameID(); target > last {
+		target = last
+	}
+	current := room.SyncData.NextFrameID.Load() - 1
+	rewound := target < current
+	if rewound {
+		room.rewindReplay()
+		resetAck := func(key uint32, value *client.Client) bool {
+			value.ResetProgress()
+			return true
+		}
+		room.ClientsContainer.Clients.Range(resetAck)
+		room.Spectators.Clients.Range(resetAck)
+	}
+	for room.SyncData.NextFrameID.Load() <= target {
+		if !room.feedReplayFrame() {
+			break
+		}
+	}
+	room.replay.progress = 0
+	log.Printf("📼 Replay in room %d seeked from frame %d to %d", room.ID, current, target)
+	return rewound
+}
+
+// acceptReplayAck 回放回退后，丢弃客户端在跳转前发出的、超过当前进度的 ack
+func (room *Room) acceptReplayAck(ack uint32) bool {
+	return !room.IsReplay() || ack < room.SyncData.NextFrameID.Load()
+}
+
+// handleReplayMessage 处理回放房间中玩家发来的消息
+// 只接受帧确认、回放控制与房主转让，其余请求一律拒绝
+func (room *Room) handleReplayMessage(from *client.Client, payload any) {
+	switch p := payload.(type) {
+	case *messages.SessionRequest_InGameFrames:
+		if p.InGameFrames != nil && room.acceptReplayAck(p.InGameFrames.GetAckFrameId()) {
+			room.updateAck(from, p.InGameFrames.GetAckFrameId())
+		}
+	case *messages.SessionRequest_ReplayControl:
+		room.handleReplayControl(from, p)
+	case *messages.SessionRequest_TransferOwner:
+		room.handleTransferOwner(from, p)
+	default:
+		log.Printf("⚠️ Replay room %d rejected request %T from player %d", room.ID, payload, from.GetID())
+	}
+}
+
+// handleReplayControl 处理回放控制，仅房主可以发起
+func (room *Room) handleReplayControl(from *client.Client, payload *messages.SessionRequest_ReplayControl) {
+	if payload == nil || payload.ReplayControl == nil {
+		return
+	}
+	if !room.IsOwner(from.GetID()) {
+		log.Printf("⚠️ Player %d is not the owner of replay room %d, control rejected", from.GetID(), room.ID)
+		return
+	}
+
+	ctrl := payload.ReplayControl
+	pb := room.replay
+	rewound := false
+	if ctrl.SeekFrameId != nil {
+		rewound = room.seekReplay(ctrl.GetSeekFrameId())
+	}
+	if ctrl.Speed != nil {
+		speed := ctrl.GetSpeed()
+		if speed > 0 && speed <= maxReplaySpeed && !math.IsNaN(float64(speed)) {
+			pb.speed = speed
+		} else {
+			log.Printf("⚠️ Replay room %d rejected speed %v from player %d", room.ID, speed, from.GetID())
+		}
+	}
+	if ctrl.Paused != nil {
+		pb.paused = ctrl.GetPaused()
+	}
+	room.broadcastReplayState(rewound)
+}
+
+// replayStateMessage 制作当前的回放播放状态
+func (room *Room) replayStateMessage(rewound bool) *messages.SessionResponse {
+	return &messages.SessionResponse{
+		Payload: &messages.SessionResponse_ReplayState{
+			ReplayState: &messages.ResponseReplayState{
+				Paused:      room.replay.paused,
+				Speed:       room.replay.speed,
+				FrameId:     room.SyncData.NextFrameID.Load() - 1,
+				LastFrameId: room.replay.source.LastFrameID(),
+				Rewound:     rewound,
+			},
+		},
+	}
+}
+
+// broadcastReplayState 向所有观众广播回放播放状态
+func (room *Room) broadcastReplayState(rewound bool) {
+	room.broadcastWithSpectators(room.replayStateMessage(rewound), []uint32{})
+}
+
+// sendReplayState 向刚加入的观众发送回放播放状态
+func (room *Room) sendReplayState(c *client.Client) {
+	if b, err := proto.Marshal(room.replayStateMessage(false)); err == nil {
+		c.WriteReliable(b)
+	}
+}
+
+// sendReplayIntro 玩家加入回放房间时，告知其房间已处于 InGame 阶段以及回放播放状态
+// 此后按正常的 lockstep 流程从第 1 帧或快照开始追帧
+func (room *Room) sendReplayIntro(c *client.Client) {
+	stageResp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_StageChange{
+			StageChange: &messages.ResponseStageChange{NewStage: uint32(constants.STAGE_InGame)},
+		},
+	}
+	if b, err := proto.Marshal(stageResp); err == nil {
+		c.WriteReliable(b)
+	}
+	room.sendReplayState(c)
+}
@@ -2,17 +2,21 @@ package room
 
 import (
 	"crypto/subtle"
+	"fmt"
 	"lockstep-core/src/config"
 	"lockstep-core/src/constants"
 	"lockstep-core/src/messages"
 	"lockstep-core/src/utils"
 
 	"lockstep-core/src/pkg/lockstep/client"
+	"lockstep-core/src/pkg/lockstep/replay"
+	"lockstep-core/src/pkg/lockstep/session"
 	lockstep_sync "lockstep-core/src/pkg/lockstep/sync"
 	"lockstep-core/src/pkg/lockstep/world"
 	"log"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/protobuf/proto"
@@ -50,15 +54,38 @@ type Room struct {
 
 	// clients
 	ClientsContainer
+	// 观战者，与玩家使用独立的容器与 ID 空间，见 spectator.go
+	Spectators ClientsContainer
 
 	// 共享数据通道
 	DataChannel
 
+	// 准备倒计时的截止时间，未在倒计时时为零值，见 ready.go
+	readyDeadline time.Time
+	// 加载阶段的截止时间，未在加载阶段或不限时时为零值，见 loading.go
+	loadingDeadline time.Time
+	// 对局的暂停状态，见 pause.go
+	pause pauseState
+	// 是否暂停中，会被房间回收器跨 goroutine 读取
+	paused atomic.Bool
+
 	// lockstep sync
-	// ticker
+	// 帧时钟，仅在 InGame 阶段存在，见 frame_clock.go
 	GameTicker *time.Ticker
+	// 悲观锁步下，开始等待当前帧输入的时间
+	frameWaitStart time.Time
+	// 本场对局最早检测到不同步的帧号，0 为尚未不同步，见 desync.go
+	firstDesyncFrame uint32
+	// 等待通知游戏世界的不同步玩家，帧号 -> 玩家 ID
+	pendingDesyncs map[uint32][]uint32
 	// data
 	SyncData *lockstep_sync.ServerSyncData
+	// 对局回放录制，仅在 InGame 阶段存在，见 recording.go
+	recorder *replay.Recorder
+	// 当前或上一场对局的回放文件路径，未配置回放目录时为空
+	recordingPath string
+	// 回放房间的播放状态，普通房间为 nil，见 replay.go
+	replay *replayPlayback
 	// config
 	config.LockstepConfig
 
@@ -68,7 +95,17 @@ type Room struct {
 	// 是否已经摧毁本房间
 	destroyOnce sync.Once
 	// 房间上次活动时间
+	// 会被房间回收器跨 goroutine 读取，请通过 UpdateActiveTime / touchActiveTime / IdleDuration 访问
 	LastActiveTime time.Time
+	activeMu       sync.RWMutex
+	// 房主的玩家 ID，见 owner.go
+	ownerID atomic.Uint32
+	// 游戏世界提供的附加信息缓存，见 info.go
+	metadata atomic.Pointer[[]byte]
+	// 请求关闭房间的通道，由房间主循环处理
+	closeRequests chan closeRequest
+	// 预告房间即将被关闭的通道，由房间主循环广播给客户端
+	closeNotices chan closeRequest
 	// 传入本房间id,通知房间管理器的停止信号通道
 	StopChan chan<- uint32
 }
@@ -90,14 +127,18 @@ func NewRoom(id uint32, stopChan chan uint32, o RoomOptions) *Room {
 	}
 	channel.Reset()
 
-	return &Room{
+	room := &Room{
 		ID:         id,
 		Name:       o.name,
 		key:        o.key,
 		JwtService: utils.NewJWTService(),
+		// clients
+		ClientsContainer: ClientsContainer{
+			SafeIDAllocator: utils.NewSafeIDAllocator(utils.RoundUpTo64(uint32(*o.MaxClientsPerRoom) + 1)),
+		},
 		// lockstep
 		GameTicker:     nil,
-		SyncData:       lockstep_sync.NewServerSyncData(),
+		SyncData:       lockstep_sync.NewServerSyncData(frameHistorySize(&o.LockstepConfig), snapshotHistorySize(&o.LockstepConfig)),
 		LockstepConfig: o.LockstepConfig,
 		// 网络
 		DataChannel: channel,
@@ -105,9 +146,33 @@ func NewRoom(id uint32, stopChan chan uint32, o RoomOptions) *Room {
 
 		RoomStage:      *constants.NewAtomStage(constants.STAGE_InLobby),
 		LastActiveTime: time.Now(),
+		closeRequests:  make(chan closeRequest, 1),
+		closeNotices:   make(chan closeRequest, 1),
 		StopChan:       stopChan,
 		destroyOnce:    sync.Once{},
 	}
+	room.Spectators = ClientsContainer{
+		SafeIDAllocator: utils.NewSafeIDAllocator(utils.RoundUpTo64(uint32(room.MaxSpectators()) + 1)),
+		idBase:          SpectatorIDBase,
+	}
+	room.ownerID.Store(noOwner)
+	return room
+}
+
+// frameHistorySize 每个房间最多保留的历史帧数量
+func frameHistorySize(cfg *config.LockstepConfig) uint32 {
+	if cfg.FrameHistorySize == nil {
+		return config.DefaultFrameHistorySize
+	}
+	return *cfg.FrameHistorySize
+}
+
+// snapshotHistorySize 每个房间最多保留的快照数量
+func snapshotHistorySize(cfg *config.LockstepConfig) uint32 {
+	if cfg.SnapshotHistorySize == nil {
+		return config.DefaultSnapshotHistorySize
+	}
+	return *cfg.SnapshotHistorySize
 }
 
 func (r *Room) IsRoomFull() bool {
@@ -118,21 +183,23 @@ func (r *Room) IsRoomFull() bool {
 func (room *Room) Reset() {
 	// lockstep sync reset
 	room.SyncData.Reset()
-	if room.GameTicker != nil {
-		room.GameTicker.Stop()
-		room.GameTicker = nil
-	}
+	room.stopFrameClock()
 	// room.Logic.Reset()
 
 	// room 本身 reset
-	room.LastActiveTime = time.Now()              // 重置最后活动时间
+	room.touchActiveTime()                        // 重置最后活动时间
 	room.RoomStage.Store(constants.STAGE_InLobby) // 重置游戏状态为大厅
 	// 清空共享数据,ingameOperations
 	room.DataChannel.Reset()
 }
 
-// Destroy 摧毁房间
+// Destroy 摧毁房间，不指明具体原因
 func (room *Room) Destroy() {
+	room.DestroyWithReason(messages.CloseReason_CLOSE_REASON_UNSPECIFIED, "room destroyed")
+}
+
+// DestroyWithReason 摧毁房间，并在关闭连接前尽力向剩余客户端发送 ResponseRoomClosed
+func (room *Room) DestroyWithReason(code messages.CloseReason, reason string) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("摧毁房间出错:捕获到 Panic: %v\n", r)
@@ -142,11 +209,8 @@ func (room *Room) Destroy() {
 	}()
 
 	room.destroyOnce.Do(func() {
-		log.Printf("🔥 Destroying room %d (stage: %d, players: %d, idle time: %v)",
-			room.ID, room.RoomStage.Load(), room.GetPlayerCount(), time.Since(room.LastActiveTime))
-
-		// TODO: 发送房间关闭消息
-		// room.RoomCtx.BroadcastMessage(...)
+		log.Printf("🔥 Destroying room %d (stage: %d, players: %d, idle time: %v, reason: %s %s)",
+			room.ID, room.RoomStage.Load(), room.GetPlayerCount(), room.IdleDuration(), code, reason)
 
 		// Game world
 		if room.Game != nil {
@@ -157,13 +221,14 @@ func (room *Room) Destroy() {
 		// 通知房间关闭
 		room.RoomStage.Store(constants.STAGE_CLOSED)
 
-		// 停止定时器
-		if room.GameTicker != nil {
-			room.GameTicker.Stop()
-			room.GameTicker = nil
-		}
+		// 停止帧时钟
+		room.stopFrameClock()
+		room.stopRecording(constants.STAGE_CLOSED, nil)
 
-		room.ClientsContainer.CloseAll()
+		// 发送房间关闭消息并关闭所有连接
+		closedMsg := roomClosedMessage(code, reason)
+		room.ClientsContainer.CloseAll(closedMsg, uint32(code), reason)
+		room.Spectators.CloseAll(closedMsg, uint32(code), reason)
 
 		// 通知房间管理器移除引用
 		room.StopChan <- room.ID
@@ -185,19 +250,60 @@ func (room *Room) HasKey() bool {
 	return room.key != ""
 }
 
+// SyncStats 获取本房间帧历史的内存占用统计，用于监控
+func (room *Room) SyncStats() lockstep_sync.SyncStats {
+	return room.SyncData.Stats()
+}
+
 // UpdateActiveTime 更新房间的最后活跃时间
 func (room *Room) UpdateActiveTime() {
-	room.LastActiveTime = time.Now()
+	room.touchActiveTime()
 	log.Printf("🕒 Updated active time for room %d", room.ID)
 }
 
+// touchActiveTime 更新房间的最后活跃时间，不打印日志，用于每帧调用
+func (room *Room) touchActiveTime() {
+	room.activeMu.Lock()
+	room.LastActiveTime = time.Now()
+	room.activeMu.Unlock()
+}
+
+// IdleDuration 房间自上次活动以来的空闲时长
+func (room *Room) IdleDuration() time.Duration {
+	room.activeMu.RLock()
+	defer room.activeMu.RUnlock()
+	return time.Since(room.LastActiveTime)
+}
+
+// RequestClose 请求房间主循环关闭本房间，可从任意 goroutine 调用
+// 房间会先向剩余客户端发送 ResponseRoomClosed 再销毁
+func (room *Room) RequestClose(code messages.CloseReason, reason string) {
+	select {
+	case room.closeRequests <- closeRequest{code: code, reason: reason}:
+	default:
+		// 已有关闭请求在等待处理
+	}
+}
+
+// NotifyClosePending 预告房间将在 remaining 后被关闭，可跨 goroutine 调用
+func (room *Room) NotifyClosePending(code messages.CloseReason, reason string, remaining time.Duration) {
+	select {
+	case room.closeNotices <- closeRequest{code: code, reason: reason, remaining: remaining}:
+	default:
+		// 已有关闭预告在等待处理
+	}
+}
+
 // RegisterPlayer 添加一个玩家到房间（发送注册信号）
-func (room *Room) RegisterPlayer(player *client.Client) {
+// 注册成功后由房间开始接收该玩家的消息
+func (room *Room) RegisterPlayer(player *client.Client) error {
 	select {
 	case room.register <- player:
 		log.Printf("🟢 Player %d registered to room %d", player.GetID(), room.ID)
+		return nil
 	default:
 		log.Printf("🔴 Failed to register player %d - channel full", player.GetID())
+		return fmt.Errorf("room %d register channel full", room.ID)
 	}
 }
 
@@ -242,6 +348,10 @@ func (room *Room) HasAllPlayerSync() bool {
 			synced = false
 			return false
 		}
+		// 断线等待重连的玩家与尚未加载完毕的玩家不参与延迟检查
+		if value.IsDisconnected() || !value.IsLoaded {
+			return true
+		}
 
 		// 获取当前玩家实际的帧号
 		playerCurrentFrame := value.ClientSyncData.LatestNextFrameID.Load()
@@ -266,50 +376,85 @@ func (room *Room) StartServeClient(client *client.Client) {
 
 	log.Printf("🟢 Starting client service for player %d", client.GetID())
 
+	// 本服务循环只负责当前会话，重连换入新会话后由新的服务循环接管
+	sess := client.GetSession()
+	gen := client.SessionGeneration()
+
 	defer func() {
 		log.Printf("🟡 StartServeClient ending for player %d", client.GetID())
 
+		if r := recover(); r != nil {
+			log.Printf("服务用户 %d 时捕获到 Panic: %v\n", client.GetID(), r)
+			log.Printf("堆栈信息:\n%s", string(debug.Stack()))
+			log.Println("程序已从 panic 中恢复，将继续运行。")
+		}
+
+		if client.SessionGeneration() != gen {
+			// 会话已被重连替换，旧会话的结束不代表玩家离开
+			log.Printf("🟡 Session of player %d was replaced, skip unregister", client.GetID())
+			return
+		}
+
 		// 发送 unregister 信号，通知房间移除这个玩家
 		select {
 		case room.unregister <- client:
 			log.Printf("🟡 Sent unregister signal for player %d", client.GetID())
 		default:
 			log.Printf("🔴 Failed to send unregister signal for player %d (channel full)", client.GetID())
-			if client != nil && client.Session != nil {
-				client.Session.Close()
+			if sess != nil {
+				sess.Close()
 			}
 		}
-
-		if r := recover(); r != nil {
-			log.Printf("服务用户 %d 时捕获到 Panic: %v\n", client.GetID(), r)
-			log.Printf("堆栈信息:\n%s", string(debug.Stack()))
-			log.Println("程序已从 panic 中恢复，将继续运行。")
-		}
 	}()
 
+	// 可靠通道的消息由单独的循环接收，会话结束时随之退出
+	// 玩家的离开只由数据报循环的结束触发
+	go room.serveReliable(client, sess)
+
 	// 接收消息循环
 	log.Printf("🟡 Starting message loop for player %d", client.GetID())
 	for {
 		// 使用 WebTransport 接收 datagram
-		rawBytes, err := client.Session.ReceiveDatagram()
+		rawBytes, err := sess.ReceiveDatagram()
 		if err != nil {
 			log.Printf("🔴 ReceiveDatagram error for player %d: %v", client.GetID(), err)
 			return
 		}
 
 		log.Printf("🟡 Received datagram from player %d, length: %d", client.GetID(), len(rawBytes))
+		room.forwardRequest(client, rawBytes)
+	}
+}
 
-		sessionRequest := &messages.SessionRequest{}
-
-		// 调用 proto.Unmarshal 进行反序列化
-		err = proto.Unmarshal(rawBytes, sessionRequest)
+// serveReliable 接收客户端通过可靠通道发来的消息，与数据报一样转发到房间主循环
+func (room *Room) serveReliable(client *client.Client, sess session.ISession) {
+	for {
+		rawBytes, err := sess.ReceiveReliable()
 		if err != nil {
-			// 如果解析失败（例如数据损坏或格式错误）
-			log.Printf("🔴 Failed to unmarshal SessionRequest: %v", err)
+			return
 		}
+		log.Printf("🟡 Received reliable message from player %d, length: %d", client.GetID(), len(rawBytes))
+		room.forwardRequest(client, rawBytes)
+	}
+}
 
-		// 发送到消息管道
-		msg := client.GetPlayerMessage(client, sessionRequest)
-		room.incomingMessages <- msg
+// forwardRequest 解析客户端发来的 SessionRequest 并送入房间主循环
+// 过大、过于频繁或无法解析的请求被丢弃并记为违规
+func (room *Room) forwardRequest(client *client.Client, rawBytes []byte) {
+	if !room.admitRaw(client, rawBytes) {
+		return
 	}
+	sessionRequest := &messages.SessionRequest{}
+
+	// 调用 proto.Unmarshal 进行反序列化
+	err := proto.Unmarshal(rawBytes, sessionRequest)
+	if err != nil {
+		// 如果解析失败（例如数据损坏或格式错误）
+		room.violate(client, fmt.Sprintf("failed to unmarshal SessionRequest: %v", err))
+		return
+	}
+
+	// 发送到消息管道
+	msg := client.GetPlayerMessage(client, sessionRequest)
+	room.incomingMessages <- msg
 }
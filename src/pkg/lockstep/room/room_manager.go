@@ -3,10 +3,12 @@ package room
 import (
 	"fmt"
 	"lockstep-core/src/config"
+	"lockstep-core/src/pkg/lockstep/replay"
 	"lockstep-core/src/pkg/lockstep/world"
 	"lockstep-core/src/utils"
 	"log"
 	"sync"
+	"sync/atomic"
 )
 
 type NewGameWorldFunc func(rctx world.IRoomContext) world.IGameWorld
@@ -23,9 +25,15 @@ type RoomManager struct {
 	// function to new game world
 	NewGameWorld NewGameWorldFunc
 
+	// 是否正在关闭，见 shutdown.go
+	shuttingDown atomic.Bool
+	// 关闭时停止空闲房间回收器
+	reaperStop chan struct{}
+
 	// cfg
 	config.LockstepConfig
 	config.ServerConfig
+	config.ReaperConfig
 }
 
 // NewRoomManager 创建一个新的 RoomManager 实例
@@ -36,14 +44,18 @@ func NewRoomManager(
 	rm := &RoomManager{
 		rooms:           make(map[uint32]*Room),
 		stopChan:        make(chan uint32, 100), // 缓冲通道
+		reaperStop:      make(chan struct{}),
 		NewGameWorld:    newFunc,
 		LockstepConfig:  cfg.LockstepConfig,
 		ServerConfig:    cfg.ServerConfig,
+		ReaperConfig:    cfg.ReaperConfig,
 		SafeIDAllocator: *utils.NewSafeIDAllocator(utils.RoundUpTo64(uint32(*cfg.MaxRoomNumber))),
 	}
 
 	// 启动监听房间停止信号的 goroutine
 	go rm.listenStopSignals()
+	// 启动空闲房间回收器，见 reaper.go
+	go rm.runReaper()
 
 	return rm
 }
@@ -69,6 +81,41 @@ func (rm *RoomManager) GetRoom(roomID uint32) (*Room, bool) {
 
 // CreateRoom 创建一个新房间
 func (rm *RoomManager) CreateRoom(name string, key string) (*Room, error) {
+	return rm.createRoom(name, key, rm.LockstepConfig, nil)
+}
+
+// CreateReplayRoom 创建一个播放回放文件的只读房间
+// 房间的帧时钟按回放录制时的帧间隔，从回放中取帧代替游戏世界的 GetFrameData，
+// 客户端与普通房间一样通过 /join 加入，房主可通过 RequestReplayControl 控制播放
+// 游戏世界仍会收到加入、离开与销毁等回调，但不会收到对局相关的回调
+func (rm *RoomManager) CreateReplayRoom(path string, name string, key string) (*Room, error) {
+	source, err := replay.LoadReplay(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load replay %s: %w", path, err)
+	}
+	if name == "" {
+		name = fmt.Sprintf("replay_%d", source.Header.GetRoomId())
+	}
+
+	// 回放房间不淘汰历史帧，需要容纳整场回放
+	cfg := rm.LockstepConfig
+	frameLimit := max(frameHistorySize(&cfg), source.LastFrameID()+1)
+	snapshotLimit := max(snapshotHistorySize(&cfg), uint32(len(source.Snapshots)))
+	cfg.FrameHistorySize = &frameLimit
+	cfg.SnapshotHistorySize = &snapshotLimit
+	if interval := source.Header.GetFrameIntervalMs(); interval > 0 {
+		cfg.FrameInterval = &interval
+	}
+	// 回放房间不再录制
+	cfg.ReplayDir = nil
+	return rm.createRoom(name, key, cfg, source)
+}
+
+// createRoom 创建并启动房间，source 不为 nil 时为回放房间
+func (rm *RoomManager) createRoom(name string, key string, cfg config.LockstepConfig, source *replay.Replay) (*Room, error) {
+	if rm.IsShuttingDown() {
+		return nil, fmt.Errorf("server is shutting down")
+	}
 	if len(rm.rooms) >= int(*rm.ServerConfig.MaxRoomNumber) {
 		return nil, fmt.Errorf("maximum number of rooms reached")
 	}
@@ -90,8 +137,11 @@ func (rm *RoomManager) CreateRoom(name string, key string) (*Room, error) {
 	room := NewRoom(roomID, rm.stopChan, RoomOptions{
 		name:           name,
 		key:            key,
-		LockstepConfig: rm.LockstepConfig,
+		LockstepConfig: cfg,
 	})
+	if source != nil {
+		room.replay = newReplayPlayback(source)
+	}
 	rm.rooms[roomID] = room
 
 	// 启动房间的状态机循环
@@ -130,6 +180,18 @@ func (rm *RoomManager) ListRooms() []uint32 {
 	return roomIDs
 }
 
+// snapshotRooms 复制当前所有房间的引用，以便在不持有锁的情况下遍历
+func (rm *RoomManager) snapshotRooms() []*Room {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	rooms := make([]*Room, 0, len(rm.rooms))
+	for _, r := range rm.rooms {
+		rooms = append(rooms, r)
+	}
+	return rooms
+}
+
 // GetRoomCount 获取房间数量
 func (rm *RoomManager) GetRoomCount() int {
 	rm.mutex.RLock()
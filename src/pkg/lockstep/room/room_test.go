@@ -0,0 +1,86 @@
+package room
+
+import (
+	"errors"
+	"lockstep-core/src/config"
+	"lockstep-core/src/pkg/lockstep/client"
+	"net"
+	"sync"
+	"testing"
+)
+
+// newTestRoom 创建使用默认配置的房间，不启动主循环
+func newTestRoom(t *testing.T, configure func(*config.LockstepConfig)) *Room {
+	t.Helper()
+	cfg := config.GeneralConfig{}
+	cfg.ApplyDefaults()
+	if configure != nil {
+		configure(&cfg.LockstepConfig)
+	}
+	room := NewRoom(1, make(chan uint32, 1), RoomOptions{name: "test", LockstepConfig: cfg.LockstepConfig})
+	t.Cleanup(room.stopFrameClock)
+	return room
+}
+
+// addTestPlayer 向房间加入一名已连接的玩家
+func addTestPlayer(t *testing.T, room *Room, uid uint32) *client.Client {
+	t.Helper()
+	c := client.NewClient(uid, &fakeSession{connected: true}, nil)
+	room.ClientsContainer.AddUser(c)
+	room.assignOwnerOnJoin(uid)
+	return c
+}
+
+// fakeSession 只记录发送内容的会话
+type fakeSession struct {
+	mu        sync.Mutex
+	connected bool
+	sent      [][]byte
+}
+
+var errFakeSessionClosed = errors.New("fake session closed")
+
+func (s *fakeSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connected = false
+	return nil
+}
+
+func (s *fakeSession) CloseWithError(code uint32, reason string) error {
+	return s.Close()
+}
+
+func (s *fakeSession) CloseWithMessage(data []byte, code uint32, reason string) error {
+	s.SendReliable(data)
+	return s.Close()
+}
+
+func (s *fakeSession) IsConnected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connected
+}
+
+func (s *fakeSession) SendDatagram(data []byte) error {
+	return s.SendReliable(data)
+}
+
+func (s *fakeSession) ReceiveDatagram() ([]byte, error) {
+	return nil, errFakeSessionClosed
+}
+
+func (s *fakeSession) SendReliable(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, data)
+	return nil
+}
+
+func (s *fakeSession) ReceiveReliable() ([]byte, error) {
+	return nil, errFakeSessionClosed
+}
+
+func (s *fakeSession) GetRemoteAddr() net.Addr {
+	return &net.UDPAddr{}
+}
@@ -0,0 +1,74 @@
+package room
+
+import (
+	"context"
+	"fmt"
+	"lockstep-core/src/config"
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"log"
+	"time"
+)
+
+// shutdownPollInterval 关闭服务器期间检查房间状态的间隔
+const shutdownPollInterval = 200 * time.Millisecond
+
+// ShutdownDrainTimeoutDuration 关闭服务器时等待 InGame 房间结束对局的最长时间
+func (rm *RoomManager) ShutdownDrainTimeoutDuration() time.Duration {
+	return seconds(rm.ServerConfig.ShutdownDrainTimeout, config.DefaultShutdownDrainTimeout)
+}
+
+// IsShuttingDown 房间管理器是否正在关闭，关闭期间不再接受新房间与新玩家
+func (rm *RoomManager) IsShuttingDown() bool {
+	return rm.shuttingDown.Load()
+}
+
+// Shutdown 关闭所有房间
+// 非 InGame 房间立即关闭，InGame 房间在 ShutdownDrainTimeout 内等待其进入 PostGame，
+// 超时后强制关闭。开始排空时所有房间先收到 ResponseClosePending 预告，
+// 关闭时以 CLOSE_REASON_SERVER_SHUTDOWN 通知客户端。
+// 在所有房间移除后返回，ctx 结束时返回 ctx.Err()
+func (rm *RoomManager) Shutdown(ctx context.Context) error {
+	if !rm.shuttingDown.CompareAndSwap(false, true) {
+		return fmt.Errorf("room manager is already shutting down")
+	}
+	// 关闭期间不再回收空闲房间，由下面统一关闭
+	close(rm.reaperStop)
+
+	drainTimeout := rm.ShutdownDrainTimeoutDuration()
+	log.Printf("🛑 Shutting down room manager: %d rooms, drain timeout %v", rm.GetRoomCount(), drainTimeout)
+
+	for _, r := range rm.snapshotRooms() {
+		r.NotifyClosePending(messages.CloseReason_CLOSE_REASON_SERVER_SHUTDOWN, "server shutting down", drainTimeout)
+	}
+
+	drainDeadline := time.Now().Add(drainTimeout)
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		rm.closeDrainedRooms(!time.Now().Before(drainDeadline))
+		if rm.GetRoomCount() == 0 {
+			log.Printf("🛑 All rooms closed")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("🔴 Shutdown deadline exceeded with %d rooms remaining", rm.GetRoomCount())
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// closeDrainedRooms 请求关闭所有无需继续等待的房间
+// force 为 true 时，InGame 房间也会被关闭
+func (rm *RoomManager) closeDrainedRooms(force bool) {
+	for _, r := range rm.snapshotRooms() {
+		// 回放房间没有需要等待的对局
+		if !force && r.RoomStage.EqualTo(constants.STAGE_InGame) && !r.IsReplay() {
+			continue
+		}
+		r.RequestClose(messages.CloseReason_CLOSE_REASON_SERVER_SHUTDOWN, "server shutting down")
+	}
+}
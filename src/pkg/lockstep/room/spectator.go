@@ -0,0 +1,178 @@
+package room
+
+import (
+	"lockstep-core/src/config"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"log"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SpectatorIDBase 观战者 ID 的起始值
+// 观战者与玩家的 ID 空间互不重叠，游戏世界可据此区分两者
+const SpectatorIDBase uint32 = 1 << 31
+
+// IsSpectatorID 判断 uid 是否为观战者 ID
+func IsSpectatorID(uid uint32) bool {
+	return uid >= SpectatorIDBase
+}
+
+// MaxSpectators 每个房间最多容纳的观战者数量，0 为不允许观战
+func (room *Room) MaxSpectators() int {
+	if room.LockstepConfig.MaxSpectatorsPerRoom == nil {
+		return config.DefaultMaxSpectatorsPerRoom
+	}
+	return int(*room.LockstepConfig.MaxSpectatorsPerRoom)
+}
+
+// IsSpectatorFull 观战席位是否已满
+func (room *Room) IsSpectatorFull() bool {
+	return room.Spectators.GetPlayerCount() >= room.MaxSpectators()
+}
+
+// SpectatorDelay 观战者收到的帧相对于最新帧延迟的帧数
+func (room *Room) SpectatorDelay() uint32 {
+	if room.LockstepConfig.SpectatorDelayFrames == nil {
+		return config.DefaultSpectatorDelayFrames
+	}
+	return *room.LockstepConfig.SpectatorDelayFrames
+}
+
+// handleSpectatorRegister 处理观战者注册
+// 观战者不占用玩家席位，也没有重连令牌，断线后需重新加入
+func (room *Room) handleSpectatorRegister(spectator *client.Client) {
+	if room.IsSpectatorFull() {
+		room.Spectators.FreeUserID(spectator.GetID())
+		room.rejectJoin(spectator, "spectator slots are full")
+		return
+	}
+	room.Spectators.AddUser(spectator)
+
+	extraData := room.Game.OnSpectatorJoin(spectator.GetID())
+	innerResp := &messages.ResponseJoin{
+		Code: 200,
+		Payload: &messages.ResponseJoin_Success{
+			Success: &messages.ResponseJoinSuccess{
+				RoomID:   room.ID,
+				MyID:     spectator.GetID(),
+				RoomInfo: room.buildRoomInfo(extraData),
+			},
+		},
+	}
+	sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_Join{Join: innerResp}}
+	if b, err := proto.Marshal(sresp); err == nil {
+		room.Spectators.SendMessageToUserByPlayer(b, spectator)
+	}
+
+	// 观战者可能在任意阶段加入，告知其房间当前阶段
+	stageResp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_StageChange{
+			StageChange: &messages.ResponseStageChange{NewStage: uint32(room.RoomStage.Load())},
+		},
+	}
+	if b, err := proto.Marshal(stageResp); err == nil {
+		room.Spectators.SendMessageToUserByPlayer(b, spectator)
+	}
+	if room.IsReplay() {
+		room.sendReplayState(spectator)
+	}
+	room.sendPauseState(spectator)
+
+	go room.StartServeClient(spectator)
+	log.Printf("👀 Spectator %d joined room %d", spectator.GetID(), room.ID)
+}
+
+// removeSpectator 将观战者移出房间并通知游戏世界
+func (room *Room) removeSpectator(spectator *client.Client) {
+	if current, ok := room.Spectators.Clients.Load(spectator.GetID()); !ok || current != spectator {
+		spectator.Close()
+		return
+	}
+	room.Spectators.DelUser(spectator.GetID())
+	spectator.Close()
+	if room.Game != nil {
+		room.Game.OnSpectatorLeave(spectator.GetID())
+	}
+	log.Printf("👀 Spectator %d left room %d", spectator.GetID(), room.ID)
+}
+
+// handleSpectatorMessage 处理观战者发来的消息
+// 观战者只能通过 RequestInGameFrames 确认收到的帧以及订阅 chunk，其余请求一律拒绝
+func (room *Room) handleSpectatorMessage(from *client.Client, payload any) {
+	switch p := payload.(type) {
+	case *messages.SessionRequest_InGameFrames:
+		if p.InGameFrames != nil && room.acceptReplayAck(p.InGameFrames.GetAckFrameId()) {
+			room.updateAck(from, p.InGameFrames.GetAckFrameId())
+		}
+	case *messages.SessionRequest_SubscribeChunks:
+		room.handleSubscribeChunks(from, p)
+	case *messages.SessionRequest_UnsubscribeChunks:
+		room.handleUnsubscribeChunks(from, p)
+	default:
+		log.Printf("⚠️ Room %d rejected request %T from spectator %d", room.ID, payload, from.GetID())
+	}
+}
+
+// broadcastWithSpectators 向所有玩家与观战者广播消息
+func (room *Room) broadcastWithSpectators(msg protoreflect.ProtoMessage, excludeIDs []uint32) {
+	room.BroadcastMessage(msg, excludeIDs)
+	room.Spectators.BroadcastMessage(msg, excludeIDs)
+}
+
+// spectatorVisibleFrame 观战者在当前可以看到的最新帧
+// 返回 false 表示游戏开始的时间还不足观战延迟
+func (room *Room) spectatorVisibleFrame(nextFrame uint32) (uint32, bool) {
+	delay := room.SpectatorDelay()
+	if nextFrame <= delay {
+		return 0, false
+	}
+	return nextFrame - delay, true
+}
+
+// spectatorPruneBound 为了观战者仍需保留的帧的下界，即在线观战者中最旧的 ack
+// 没有观战者时返回 false
+func (room *Room) spectatorPruneBound(nextFrame uint32) (uint32, bool) {
+	if room.Spectators.GetPlayerCount() == 0 {
+		return 0, false
+	}
+	visible, ok := room.spectatorVisibleFrame(nextFrame)
+	if !ok {
+		return 0, true
+	}
+	bound := visible
+	room.Spectators.Clients.Range(func(key uint32, value *client.Client) bool {
+		if ack := value.LatestAckNextFrameID.Load(); ack < bound {
+			bound = ack
+		}
+		return true
+	})
+	return bound, true
+}
+
+// sendSpectatorFrames 向观战者发送延迟后的帧
+func (room *Room) sendSpectatorFrames(nextFrame uint32) {
+	if room.Spectators.GetPlayerCount() == 0 {
+		return
+	}
+	visible, ok := room.spectatorVisibleFrame(nextFrame)
+	if !ok {
+		return
+	}
+	oldestAck, _ := room.spectatorPruneBound(nextFrame)
+	allFrames := room.collectChunkFrames(room.activeChunks(), oldestAck+1, visible)
+
+	room.Spectators.Clients.Range(func(key uint32, value *client.Client) bool {
+		ack := value.LatestAckNextFrameID.Load()
+		if ack >= visible {
+			return true
+		}
+		// 严重落后或所需帧已被淘汰，改为发送不晚于可见帧的快照
+		if room.trySnapshotCatchUp(value, ack, visible) {
+			return true
+		}
+		room.sendFrames(value, framesFor(value, ack, allFrames), visible, 0)
+		return true
+	})
+}
@@ -0,0 +1,135 @@
+package room
+
+import (
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"lockstep-core/src/pkg/lockstep/client"
+	"log"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// anyStage 房间的所有正常阶段
+var anyStage = []constants.Stage{
+	constants.STAGE_InLobby,
+	constants.STAGE_Preparing,
+	constants.STAGE_Loading,
+	constants.STAGE_InGame,
+	constants.STAGE_PostGame,
+}
+
+// requestStages 各类请求合法的阶段，未列出的请求在任何阶段均不合法
+// 阶段之间允许的切换见 constants.Stage.CanTransitionTo
+// 回放控制只在回放房间中有意义，回放房间始终处于 InGame 阶段
+// 加载超时后不再等待而开始游戏时，未加载完毕的玩家仍可在 InGame 阶段报告加载完毕
+func requestStages(payload any) []constants.Stage {
+	switch payload.(type) {
+	case *messages.SessionRequest_InLobby, *messages.SessionRequest_ToPreparing:
+		return []constants.Stage{constants.STAGE_InLobby}
+	case *messages.SessionRequest_Ready:
+		return []constants.Stage{constants.STAGE_Preparing}
+	case *messages.SessionRequest_ToInLobby:
+		return []constants.Stage{constants.STAGE_Preparing, constants.STAGE_PostGame}
+	case *messages.SessionRequest_Loaded:
+		return []constants.Stage{constants.STAGE_Loading, constants.STAGE_InGame}
+	case *messages.SessionRequest_InGameFrames,
+		*messages.SessionRequest_EndGame,
+		*messages.SessionRequest_Pause,
+		*messages.SessionRequest_Resume,
+		*messages.SessionRequest_ReplayControl:
+		return []constants.Stage{constants.STAGE_InGame}
+	case *messages.SessionRequest_PostGameData:
+		return []constants.Stage{constants.STAGE_PostGame}
+	case *messages.SessionRequest_Other,
+		*messages.SessionRequest_TransferOwner,
+		*messages.SessionRequest_SubscribeChunks,
+		*messages.SessionRequest_UnsubscribeChunks:
+		return anyStage
+	default:
+		return nil
+	}
+}
+
+// payloadAllowedInStage 请求在房间处于 stage 阶段时是否合法
+func payloadAllowedInStage(payload any, stage constants.Stage) bool {
+	for _, allowed := range requestStages(payload) {
+		if allowed == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// sendError 告知客户端其请求被拒绝的原因
+func (room *Room) sendError(c *client.Client, code messages.ErrorCode, message string) {
+	resp := &messages.SessionResponse{
+		Payload: &messages.SessionResponse_Error{
+			Error: &messages.ResponseError{Code: code, Message: message},
+		},
+	}
+	if b, err := proto.Marshal(resp); err == nil {
+		c.WriteReliable(b)
+	}
+}
+
+// changeStage 切换房间阶段并向所有客户端广播 ResponseStageChange
+// 帧时钟的启动与停止统一在此处理：
+// 进入 InGame 时启动，离开 InGame 时停止
+// 不合法的切换会被拒绝并返回 false
+func (room *Room) changeStage(newStage constants.Stage, data []byte) bool {
+	oldStage := room.RoomStage.Load()
+	if !oldStage.CanTransitionTo(newStage) {
+		log.Printf("⚠️ Room %d rejected stage change 0x%x -> 0x%x", room.ID, uint32(oldStage), uint32(newStage))
+		return false
+	}
+	room.RoomStage.Store(newStage)
+	if oldStage == constants.STAGE_Preparing {
+		room.readyDeadline = time.Time{}
+	}
+	room.loadingDeadline = time.Time{}
+	if timeout := room.LoadingTimeoutDuration(); newStage == constants.STAGE_Loading && timeout > 0 {
+		room.loadingDeadline = time.Now().Add(timeout)
+	}
+
+	innerStage := &messages.ResponseStageChange{
+		NewStage: uint32(newStage),
+		Data:     data,
+	}
+	sresp := &messages.SessionResponse{Payload: &messages.SessionResponse_StageChange{StageChange: innerStage}}
+	room.broadcastWithSpectators(sresp, []uint32{})
+
+	switch {
+	case newStage == constants.STAGE_InGame:
+		room.startGame()
+		room.startRecording(data)
+	case oldStage == constants.STAGE_InGame:
+		room.clearPause()
+		room.stopFrameClock()
+		room.stopRecording(newStage, data)
+	}
+
+	if newStage == constants.STAGE_InLobby {
+		// 回到大厅，为下一场游戏重置帧同步与玩家状态
+		room.SyncData.Reset()
+		room.ClientsContainer.Reset()
+		room.Spectators.Reset()
+	}
+
+	if room.Game != nil {
+		room.Game.OnStageChanged(oldStage, newStage)
+	}
+	return true
+}
+
+// startGame 进入 InGame 阶段，通知游戏世界并启动帧时钟
+// 第 1 帧将在一个帧间隔之后由帧时钟触发
+func (room *Room) startGame() {
+	room.SyncData.Reset()
+	room.frameWaitStart = time.Time{}
+	room.resetDesync()
+	if room.Game != nil {
+		room.Game.OnGameStart(room.FrameInterval())
+	}
+	room.startFrameClock()
+}
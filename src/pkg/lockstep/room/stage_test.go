@@ -0,0 +1,33 @@
+package room
+
+import (
+	"lockstep-core/src/constants"
+	"lockstep-core/src/messages"
+	"testing"
+)
+
+func TestPayloadAllowedInStage(t *testing.T) {
+	cases := []struct {
+		name    string
+		payload any
+		stage   constants.Stage
+		want    bool
+	}{
+		{"ready in preparing", &messages.SessionRequest_Ready{}, constants.STAGE_Preparing, true},
+		{"ready in lobby", &messages.SessionRequest_Ready{}, constants.STAGE_InLobby, false},
+		{"loaded in loading", &messages.SessionRequest_Loaded{}, constants.STAGE_Loading, true},
+		{"loaded in game", &messages.SessionRequest_Loaded{}, constants.STAGE_InGame, true},
+		{"loaded in post game", &messages.SessionRequest_Loaded{}, constants.STAGE_PostGame, false},
+		{"pause in game", &messages.SessionRequest_Pause{}, constants.STAGE_InGame, true},
+		{"resume in loading", &messages.SessionRequest_Resume{}, constants.STAGE_Loading, false},
+		{"back to lobby after game", &messages.SessionRequest_ToInLobby{}, constants.STAGE_PostGame, true},
+		{"other in any stage", &messages.SessionRequest_Other{}, constants.STAGE_Loading, true},
+		{"nil payload", nil, constants.STAGE_InGame, false},
+		{"closed room", &messages.SessionRequest_Other{}, constants.STAGE_CLOSED, false},
+	}
+	for _, tc := range cases {
+		if got := payloadAllowedInStage(tc.payload, tc.stage); got != tc.want {
+			t.Errorf("%s: payloadAllowedInStage = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
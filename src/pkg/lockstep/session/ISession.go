@@ -2,11 +2,20 @@ package session
 
 import "net"
 
+// ISession 客户端会话
+// 提供两种通道：不可靠的数据报，用于帧数据；可靠有序的消息，用于加入、阶段变更等控制消息
 type ISession interface {
 	Close() error
 	CloseWithError(code uint32, reason string) error
+	// CloseWithMessage 尽力先送达最后一条消息再关闭连接
+	// 例如房间关闭或被踢出时告知客户端原因，发送失败不会阻止关闭
+	CloseWithMessage(data []byte, code uint32, reason string) error
 	IsConnected() bool
 	SendDatagram(data []byte) error
 	ReceiveDatagram() ([]byte, error)
+	// SendReliable 通过可靠有序的通道发送一条消息
+	SendReliable(data []byte) error
+	// ReceiveReliable 从可靠有序的通道接收一条消息，阻塞直到收到消息或会话结束
+	ReceiveReliable() ([]byte, error)
 	GetRemoteAddr() net.Addr
 }
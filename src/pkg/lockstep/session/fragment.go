@@ -0,0 +1,180 @@
+package session
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 超过 MaxDatagramSize 的数据报会被拆分为多个分片发送，接收端重组后再交给上层
+// 分片格式：fragmentMarker (1) + 消息编号 (2, 大端) + 分片序号 (1) + 分片总数 (1) + 分片内容
+// protobuf 消息不会以 0xFF 开头，因此可与完整的数据报区分
+const (
+	fragmentMarker     = 0xFF
+	fragmentHeaderSize = 5
+	// maxFragments 单条消息最多拆分的分片数量
+	maxFragments = 255
+)
+
+const (
+	// MaxDatagramSize 单个数据报的最大字节数，超过时分片发送
+	// 低于 QUIC 在最小路径 MTU 下允许的数据报大小，并为 WebTransport 的头部留出余量
+	MaxDatagramSize = 1100
+	// ReassemblyTimeout 分片未能在此时间内集齐时丢弃整条消息
+	ReassemblyTimeout = 5 * time.Second
+	// ReassemblyMemoryLimit 每个会话用于重组的分片内容的最大字节数
+	// 超出时丢弃最早开始重组的消息
+	ReassemblyMemoryLimit = 1 << 20
+)
+
+var (
+	// ErrTooManyFragments 消息需要的分片数量超过 maxFragments，应改用可靠通道
+	ErrTooManyFragments = errors.New("session: message needs too many fragments")
+	// ErrBadFragment 分片头部不合法
+	ErrBadFragment = errors.New("session: malformed fragment")
+)
+
+// isFragment 判断数据报是否为分片
+func isFragment(data []byte) bool {
+	return len(data) > 0 && data[0] == fragmentMarker
+}
+
+// Fragmenter 将过大的数据报拆分为分片
+type Fragmenter struct {
+	nextID atomic.Uint32
+}
+
+// Split 拆分 data，不超过 MaxDatagramSize 时原样返回
+func (f *Fragmenter) Split(data []byte) ([][]byte, error) {
+	if len(data) <= MaxDatagramSize {
+		return [][]byte{data}, nil
+	}
+	chunkSize := MaxDatagramSize - fragmentHeaderSize
+	count := (len(data) + chunkSize - 1) / chunkSize
+	if count > maxFragments {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooManyFragments, len(data))
+	}
+
+	msgID := uint16(f.nextID.Add(1))
+	fragments := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		chunk := data[i*chunkSize : min((i+1)*chunkSize, len(data))]
+		fragment := make([]byte, fragmentHeaderSize+len(chunk))
+		fragment[0] = fragmentMarker
+		binary.BigEndian.PutUint16(fragment[1:3], msgID)
+		fragment[3] = byte(i)
+		fragment[4] = byte(count)
+		copy(fragment[fragmentHeaderSize:], chunk)
+		fragments = append(fragments, fragment)
+	}
+	return fragments, nil
+}
+
+// partialMessage 正在重组的消息
+type partialMessage struct {
+	fragments [][]byte
+	received  int
+	size      int
+	startedAt time.Time
+}
+
+// Reassembler 重组收到的分片，每个会话一个
+type Reassembler struct {
+	mu      sync.Mutex
+	partial map[uint16]*partialMessage
+	// 所有正在重组的消息已收到的分片内容字节数
+	size int
+}
+
+// NewReassembler 创建分片重组器
+func NewReassembler() *Reassembler {
+	return &Reassembler{partial: make(map[uint16]*partialMessage)}
+}
+
+// Add 加入一个分片，集齐所有分片时返回重组后的消息
+// 重复的分片会被忽略；超时或超出内存上限的消息会被丢弃
+func (r *Reassembler) Add(fragment []byte, now time.Time) ([]byte, bool, error) {
+	if len(fragment) <= fragmentHeaderSize || fragment[0] != fragmentMarker {
+		return nil, false, ErrBadFragment
+	}
+	msgID := binary.BigEndian.Uint16(fragment[1:3])
+	index, count := int(fragment[3]), int(fragment[4])
+	if count < 2 || index >= count {
+		return nil, false, ErrBadFragment
+	}
+	chunk := fragment[fragmentHeaderSize:]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expire(now)
+
+	pm, ok := r.partial[msgID]
+	if !ok {
+		pm = &partialMessage{fragments: make([][]byte, count), startedAt: now}
+		r.partial[msgID] = pm
+	} else if len(pm.fragments) != count {
+		// 编号回绕后被新消息复用，丢弃旧消息
+		r.drop(msgID)
+		pm = &partialMessage{fragments: make([][]byte, count), startedAt: now}
+		r.partial[msgID] = pm
+	}
+	if pm.fragments[index] != nil {
+		return nil, false, nil
+	}
+
+	for r.size+len(chunk) > ReassemblyMemoryLimit {
+		oldest := r.oldest()
+		r.drop(oldest)
+		if oldest == msgID {
+			return nil, false, fmt.Errorf("session: reassembly memory limit exceeded, message %d dropped", msgID)
+		}
+	}
+
+	pm.fragments[index] = append([]byte(nil), chunk...)
+	pm.received++
+	pm.size += len(chunk)
+	r.size += len(chunk)
+	if pm.received < count {
+		return nil, false, nil
+	}
+
+	msg := make([]byte, 0, pm.size)
+	for _, f := range pm.fragments {
+		msg = append(msg, f...)
+	}
+	r.drop(msgID)
+	return msg, true, nil
+}
+
+// expire 丢弃超时未集齐的消息
+func (r *Reassembler) expire(now time.Time) {
+	for msgID, pm := range r.partial {
+		if now.Sub(pm.startedAt) > ReassemblyTimeout {
+			r.drop(msgID)
+		}
+	}
+}
+
+// oldest 最早开始重组的消息，调用方需保证至少有一条
+func (r *Reassembler) oldest() uint16 {
+	var oldestID uint16
+	var oldestAt time.Time
+	first := true
+	for msgID, pm := range r.partial {
+		if first || pm.startedAt.Before(oldestAt) {
+			oldestID, oldestAt, first = msgID, pm.startedAt, false
+		}
+	}
+	return oldestID
+}
+
+// drop 丢弃一条正在重组的消息
+func (r *Reassembler) drop(msgID uint16) {
+	if pm, ok := r.partial[msgID]; ok {
+		r.size -= pm.size
+		delete(r.partial, msgID)
+	}
+}
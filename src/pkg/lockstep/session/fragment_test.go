@@ -0,0 +1,133 @@
+package session
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testPayload 生成不以 fragmentMarker 开头的测试数据
+func testPayload(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestFragmentRoundTrip(t *testing.T) {
+	var f Fragmenter
+	r := NewReassembler()
+	now := time.Unix(1000, 0)
+	data := testPayload(MaxDatagramSize*3 + 17)
+
+	fragments, err := f.Split(data)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if len(fragments) != 4 {
+		t.Fatalf("Split produced %d fragments, want 4", len(fragments))
+	}
+	// 倒序送达并重复其中一个分片
+	for i := len(fragments) - 1; i > 0; i-- {
+		if !isFragment(fragments[i]) || len(fragments[i]) > MaxDatagramSize {
+			t.Fatalf("fragment %d is malformed (%d bytes)", i, len(fragments[i]))
+		}
+		if _, complete, err := r.Add(fragments[i], now); err != nil || complete {
+			t.Fatalf("Add fragment %d: complete=%v err=%v", i, complete, err)
+		}
+	}
+	if _, complete, err := r.Add(fragments[1], now); err != nil || complete {
+		t.Fatalf("duplicate fragment: complete=%v err=%v", complete, err)
+	}
+	msg, complete, err := r.Add(fragments[0], now)
+	if err != nil || !complete {
+		t.Fatalf("last fragment: complete=%v err=%v", complete, err)
+	}
+	if !bytes.Equal(msg, data) {
+		t.Fatal("reassembled message differs from the original")
+	}
+	if r.size != 0 || len(r.partial) != 0 {
+		t.Fatalf("reassembler still holds %d bytes in %d messages", r.size, len(r.partial))
+	}
+}
+
+func TestSplitSmallDatagramUnchanged(t *testing.T) {
+	var f Fragmenter
+	data := testPayload(MaxDatagramSize)
+	fragments, err := f.Split(data)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if len(fragments) != 1 || !bytes.Equal(fragments[0], data) {
+		t.Fatal("datagram within MaxDatagramSize was modified")
+	}
+}
+
+func TestSplitTooManyFragments(t *testing.T) {
+	var f Fragmenter
+	_, err := f.Split(make([]byte, (MaxDatagramSize-fragmentHeaderSize)*maxFragments+1))
+	if !errors.Is(err, ErrTooManyFragments) {
+		t.Fatalf("Split error = %v, want ErrTooManyFragments", err)
+	}
+}
+
+func TestReassemblerRejectsBadFragments(t *testing.T) {
+	r := NewReassembler()
+	now := time.Unix(1000, 0)
+	bad := [][]byte{
+		{fragmentMarker, 0, 1, 0, 2},       // 没有内容
+		{0x0A, 0, 1, 0, 2, 1},              // 不是分片
+		{fragmentMarker, 0, 1, 0, 1, 1},    // 只有一个分片
+		{fragmentMarker, 0, 1, 3, 2, 1, 2}, // 序号超出总数
+	}
+	for i, fragment := range bad {
+		if _, _, err := r.Add(fragment, now); !errors.Is(err, ErrBadFragment) {
+			t.Errorf("fragment %d: error = %v, want ErrBadFragment", i, err)
+		}
+	}
+}
+
+func TestReassemblerExpiresIncompleteMessages(t *testing.T) {
+	var f Fragmenter
+	r := NewReassembler()
+	now := time.Unix(1000, 0)
+	fragments, _ := f.Split(testPayload(MaxDatagramSize * 2))
+	r.Add(fragments[0], now)
+
+	other, _ := f.Split(testPayload(MaxDatagramSize * 2))
+	r.Add(other[0], now.Add(ReassemblyTimeout+time.Second))
+	if _, ok := r.partial[0x0001]; ok {
+		t.Fatal("expired message is still being reassembled")
+	}
+	if _, complete, _ := r.Add(fragments[1], now.Add(ReassemblyTimeout+time.Second)); complete {
+		t.Fatal("expired message was completed")
+	}
+}
+
+func TestReassemblerMemoryLimit(t *testing.T) {
+	var f Fragmenter
+	r := NewReassembler()
+	now := time.Unix(1000, 0)
+	chunkSize := MaxDatagramSize - fragmentHeaderSize
+
+	// 每条消息只送达除最后一个以外的分片，直到超出内存上限
+	perMessage := maxFragments - 1
+	messages := ReassemblyMemoryLimit/(perMessage*chunkSize) + 2
+	for m := 0; m < messages; m++ {
+		fragments, err := f.Split(testPayload(chunkSize * maxFragments))
+		if err != nil {
+			t.Fatalf("Split: %v", err)
+		}
+		for _, fragment := range fragments[:perMessage] {
+			r.Add(fragment, now.Add(time.Duration(m)*time.Millisecond))
+		}
+	}
+	if r.size > ReassemblyMemoryLimit {
+		t.Fatalf("reassembler holds %d bytes, limit is %d", r.size, ReassemblyMemoryLimit)
+	}
+	if _, ok := r.partial[1]; ok {
+		t.Fatal("oldest message was not dropped when the memory limit was exceeded")
+	}
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxReliableMessageSize 可靠通道单条消息的最大字节数
+const MaxReliableMessageSize = 1 << 20
+
+// ErrMessageTooLarge 可靠通道的消息超过 MaxReliableMessageSize
+var ErrMessageTooLarge = errors.New("session: reliable message too large")
+
+// 可靠通道的分帧格式：4 字节大端长度前缀 + 消息内容
+// WebTransport 在单向流上连续写入，WebSocket 在二进制消息中以 wsReliableMarker 开头后写入
+
+// writeFrame 写入一条带长度前缀的消息
+// 长度前缀与内容合并为一次写入，避免并发写入方交错
+func writeFrame(w io.Writer, data []byte) error {
+	if len(data) > MaxReliableMessageSize {
+		return fmt.Errorf("%w: %d bytes", ErrMessageTooLarge, len(data))
+	}
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	_, err := w.Write(buf)
+	return err
+}
+
+// readFrame 读取一条带长度前缀的消息
+// 在消息边界处结束时返回 io.EOF，消息被截断时返回 io.ErrUnexpectedEOF
+func readFrame(r io.Reader) ([]byte, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(prefix[:])
+	if size > MaxReliableMessageSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrMessageTooLarge, size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return data, nil
+}
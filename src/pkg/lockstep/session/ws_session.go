@@ -1,8 +1,11 @@
 package session
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"sync"
@@ -10,22 +13,44 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// wsReliableMarker 可靠消息在 WebSocket 二进制消息中的首字节
+// 之后是一条或多条与 WebTransport 单向流相同的带长度前缀的消息
+// protobuf 消息不会以 0x00 开头，因此可与数据报区分
+const wsReliableMarker = 0x00
+
+// wsInboxSize 已读出但尚未被取走的消息数量上限
+const wsInboxSize = 64
+
 // WebsocketSession 实现了与 WtSession 兼容的接口，用于 WebSocket 连接。
+// WebSocket 本身可靠有序，数据报与可靠消息共用同一连接，接收时按首字节区分
+// WebSocket 消息没有大小限制，数据报不需要分片
 type WebsocketSession struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	// gorilla/websocket 连接
 	conn  *websocket.Conn
 	mutex sync.Mutex // 用于保护对 conn 的并发访问
+
+	// 读取循环分发的数据报与可靠消息
+	datagramIn chan []byte
+	reliableIn chan []byte
+	// 首次接收时启动读取循环
+	readOnce sync.Once
+	// 读取循环结束时关闭，此后 readErr 有效
+	readDone chan struct{}
+	readErr  error
 }
 
 // NewWebsocketSession 创建一个新的 WebsocketSession 实例。
 func NewWebsocketSession(conn *websocket.Conn) *WebsocketSession {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &WebsocketSession{
-		conn:   conn,
-		ctx:    ctx,
-		cancel: cancel,
+		conn:       conn,
+		ctx:        ctx,
+		cancel:     cancel,
+		datagramIn: make(chan []byte, wsInboxSize),
+		reliableIn: make(chan []byte, wsInboxSize),
+		readDone:   make(chan struct{}),
 	}
 }
 
@@ -57,6 +82,11 @@ func (ws *WebsocketSession) IsConnected() bool {
 	ws.mutex.Lock()
 	defer ws.mutex.Unlock()
 
+	return ws.isConnectedLocked()
+}
+
+// isConnectedLocked 同 IsConnected，调用方需已持有 mutex
+func (ws *WebsocketSession) isConnectedLocked() bool {
 	// 检查 conn 是否为 nil，并检查会话的 context 是否已被取消。
 	return ws.conn != nil && ws.ctx.Err() == nil
 }
@@ -67,7 +97,7 @@ func (ws *WebsocketSession) SendDatagram(data []byte) error {
 	ws.mutex.Lock()
 	defer ws.mutex.Unlock()
 
-	if !ws.IsConnected() {
+	if !ws.isConnectedLocked() {
 		return fmt.Errorf("session is closed or nil")
 	}
 
@@ -82,25 +112,107 @@ func (ws *WebsocketSession) SendDatagram(data []byte) error {
 // ReceiveDatagram 从 WebSocket 连接接收数据。
 // 同样，我们读取二进制消息作为 "datagram"。
 func (ws *WebsocketSession) ReceiveDatagram() ([]byte, error) {
-	// 注意：gorilla/websocket 的 ReadMessage 会阻塞，直到有消息、发生错误或连接关闭。
-	// 它的行为受到我们创建的 context 的控制。
-	// 当 context 被取消时，并发的读取操作应该会出错。
+	return ws.receive(ws.datagramIn)
+}
+
+// SendReliable 发送一条可靠消息，格式见 wsReliableMarker
+func (ws *WebsocketSession) SendReliable(data []byte) error {
+	var buf bytes.Buffer
+	buf.WriteByte(wsReliableMarker)
+	if err := writeFrame(&buf, data); err != nil {
+		return err
+	}
+
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
 
+	if !ws.isConnectedLocked() {
+		return fmt.Errorf("session is closed or nil")
+	}
+	err := ws.conn.WriteMessage(websocket.BinaryMessage, buf.Bytes())
+	if err != nil {
+		log.Printf("🔴 SendReliable (WebSocket) error for player %v: %v", ws.conn.RemoteAddr(), err)
+	}
+	return err
+}
+
+// ReceiveReliable 接收客户端发来的可靠消息
+func (ws *WebsocketSession) ReceiveReliable() ([]byte, error) {
+	return ws.receive(ws.reliableIn)
+}
+
+// receive 从读取循环分发的 inbox 中取出一条消息
+func (ws *WebsocketSession) receive(inbox <-chan []byte) ([]byte, error) {
 	if !ws.IsConnected() {
 		return nil, fmt.Errorf("session is closed or nil")
 	}
+	ws.readOnce.Do(func() {
+		go ws.readLoop()
+	})
+	select {
+	case data := <-inbox:
+		return data, nil
+	case <-ws.readDone:
+		// 读取循环结束前已分发的消息仍可取走
+		select {
+		case data := <-inbox:
+			return data, nil
+		default:
+			return nil, ws.readErr
+		}
+	}
+}
 
-	// ReadMessage 是线程安全的，所以这里不需要锁
-	_, data, err := ws.conn.ReadMessage()
-	if err != nil {
-		// 检查错误类型，如果是预期的关闭，则不打印为错误日志
-		if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
-			log.Printf("🔴 ReceiveDatagram (WebSocket) error: %v", err)
+// readLoop 持续读取二进制消息并按首字节分发为数据报或可靠消息
+func (ws *WebsocketSession) readLoop() {
+	defer close(ws.readDone)
+	for {
+		// 注意：gorilla/websocket 的 ReadMessage 会阻塞，直到有消息、发生错误或连接关闭。
+		// 只有本循环读取连接，因此这里不需要锁
+		_, data, err := ws.conn.ReadMessage()
+		if err != nil {
+			// 检查错误类型，如果是预期的关闭，则不打印为错误日志
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("🔴 ReceiveDatagram (WebSocket) error: %v", err)
+			}
+			ws.readErr = err
+			return
+		}
+
+		if len(data) == 0 || data[0] != wsReliableMarker {
+			if !ws.deliver(ws.datagramIn, data) {
+				return
+			}
+			continue
+		}
+
+		r := bytes.NewReader(data[1:])
+		for {
+			msg, err := readFrame(r)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				ws.readErr = fmt.Errorf("malformed reliable message: %w", err)
+				log.Printf("🔴 ReceiveReliable (WebSocket) error: %v", ws.readErr)
+				return
+			}
+			if !ws.deliver(ws.reliableIn, msg) {
+				return
+			}
 		}
-		return nil, err
 	}
+}
 
-	return data, nil
+// deliver 将消息放入 inbox，会话关闭时返回 false
+func (ws *WebsocketSession) deliver(inbox chan<- []byte, data []byte) bool {
+	select {
+	case inbox <- data:
+		return true
+	case <-ws.ctx.Done():
+		ws.readErr = ws.ctx.Err()
+		return false
+	}
 }
 
 // RemoteAddr 返回客户端的网络地址
@@ -119,3 +231,30 @@ func (ws *WebsocketSession) CloseWithError(code uint32, reason string) error {
 	}
 	return ws.conn.CloseHandler()(int(code), reason)
 }
+
+// wsCloseCodeBase WebSocket 应用自定义关闭码的起始值 (4000-4999)
+const wsCloseCodeBase = 4000
+
+// CloseWithMessage 发送最后一条可靠消息后发送关闭帧并关闭连接
+// WebSocket 基于 TCP，写入成功的消息会先于关闭帧送达
+func (ws *WebsocketSession) CloseWithMessage(data []byte, code uint32, reason string) error {
+	if err := ws.SendReliable(data); err != nil {
+		log.Printf("🔴 SendReliable (final) error: %v", err)
+	}
+
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if ws.cancel != nil {
+		ws.cancel()
+		ws.cancel = nil
+	}
+	if ws.conn == nil {
+		return fmt.Errorf("session is nil")
+	}
+	closeMsg := websocket.FormatCloseMessage(wsCloseCodeBase+int(code), reason)
+	if err := ws.conn.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
+		log.Printf("🔴 Write close frame (WebSocket) error: %v", err)
+	}
+	return ws.conn.Close()
+}
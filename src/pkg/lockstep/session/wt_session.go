@@ -2,62 +2,216 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/quic-go/webtransport-go"
 )
 
+// closeLinger 发送最后一条数据报后延迟关闭会话的时间
+// 数据报不保证送达，立即关闭会话很可能使其被丢弃
+const closeLinger = 100 * time.Millisecond
+
+const (
+	// openStreamTimeout 打开可靠通道单向流的超时时间
+	openStreamTimeout = 5 * time.Second
+	// reliableWriteTimeout 可靠消息写入的超时时间，客户端长时间不读取时放弃写入
+	reliableWriteTimeout = 5 * time.Second
+	// reliableInboxSize 已读出但尚未被取走的可靠消息数量上限
+	reliableInboxSize = 64
+)
+
 // webtransport session
 
 type WtSession struct {
 	ctx    context.Context
 	cancel context.CancelFunc
+	// Close 与 CloseWithMessage 可能在不同 goroutine 中调用，cancel 只经由 stop 调用一次
+	stopOnce sync.Once
 	// webtransport 会话
 	session *webtransport.Session
+	// 正在延迟关闭，此时不再视为已连接
+	closing atomic.Bool
+
+	// 服务端到客户端的可靠通道，首次发送时打开的单向流
+	sendStream *webtransport.SendStream
+	// 保护 sendStream 的打开与写入
+	sendMu sync.Mutex
+	// 从客户端打开的单向流中读出的可靠消息
+	reliableIn chan []byte
+	// 首次 ReceiveReliable 时开始接受客户端的单向流
+	acceptOnce sync.Once
+
+	// 过大数据报的分片与重组，见 fragment.go
+	fragmenter  Fragmenter
+	reassembler *Reassembler
 }
 
 func NewWtSession(session *webtransport.Session) *WtSession {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &WtSession{
-		session: session,
-		ctx:     ctx,
-		cancel:  cancel,
+		session:     session,
+		ctx:         ctx,
+		cancel:      cancel,
+		reliableIn:  make(chan []byte, reliableInboxSize),
+		reassembler: NewReassembler(),
 	}
 }
 
 func (ws *WtSession) Close() error {
-	if ws.cancel != nil {
-		ws.cancel()
-		ws.cancel = nil
+	if ws.closing.Load() {
+		// CloseWithMessage 已安排延迟关闭
+		return nil
 	}
+	ws.stop()
 	return ws.session.CloseWithError(0, "player disconnected")
 }
 
+// stop 结束会话的读取，可重复调用
+func (ws *WtSession) stop() {
+	ws.stopOnce.Do(ws.cancel)
+}
+
 func (ws *WtSession) IsConnected() bool {
-	return ws.session != nil && ws.session.Context().Err() == nil
+	return ws.session != nil && !ws.closing.Load() && ws.session.Context().Err() == nil
 }
+
+// SendDatagram 发送数据报，超过 MaxDatagramSize 时拆分为多个分片
+// 分片过多的消息改为通过可靠通道发送
 func (ws *WtSession) SendDatagram(data []byte) error {
 	if !ws.IsConnected() {
 		return fmt.Errorf("session is nil")
 	}
-	err := ws.session.SendDatagram(data)
+	fragments, err := ws.fragmenter.Split(data)
 	if err != nil {
-		log.Printf("🔴 SendDatagram error for player %v: %v", ws.session.RemoteAddr(), err)
+		log.Printf("🟠 Datagram of %d bytes for player %v sent reliably: %v", len(data), ws.session.RemoteAddr(), err)
+		return ws.writeReliable(data)
 	}
-	return err
+	for _, fragment := range fragments {
+		if err := ws.session.SendDatagram(fragment); err != nil {
+			log.Printf("🔴 SendDatagram error for player %v: %v", ws.session.RemoteAddr(), err)
+			return err
+		}
+	}
+	return nil
 }
 
+// ReceiveDatagram 接收数据报，分片在集齐后作为一条完整的数据报返回
 func (ws *WtSession) ReceiveDatagram() ([]byte, error) {
 	if !ws.IsConnected() {
 		return nil, fmt.Errorf("session is nil")
 	}
-	data, err := ws.session.ReceiveDatagram(ws.ctx)
-	if err != nil {
-		log.Printf("🔴 ReceiveDatagram error: %v", err)
+	for {
+		data, err := ws.session.ReceiveDatagram(ws.ctx)
+		if err != nil {
+			log.Printf("🔴 ReceiveDatagram error: %v", err)
+			return data, err
+		}
+		if !isFragment(data) {
+			return data, nil
+		}
+		msg, complete, err := ws.reassembler.Add(data, time.Now())
+		if err != nil {
+			log.Printf("🟠 Dropped fragment from player %v: %v", ws.session.RemoteAddr(), err)
+			continue
+		}
+		if complete {
+			return msg, nil
+		}
+	}
+}
+
+// SendReliable 在服务端打开的单向流上写入一条带长度前缀的消息
+// 所有可靠消息共用同一条流，从而保证顺序
+func (ws *WtSession) SendReliable(data []byte) error {
+	if !ws.IsConnected() {
+		return fmt.Errorf("session is nil")
+	}
+	return ws.writeReliable(data)
+}
+
+// writeReliable 写入可靠消息，不检查会话状态
+// 写入失败时放弃当前流，下一次发送会重新打开
+func (ws *WtSession) writeReliable(data []byte) error {
+	ws.sendMu.Lock()
+	defer ws.sendMu.Unlock()
+
+	if ws.sendStream == nil {
+		ctx, cancel := context.WithTimeout(ws.session.Context(), openStreamTimeout)
+		defer cancel()
+		str, err := ws.session.OpenUniStreamSync(ctx)
+		if err != nil {
+			log.Printf("🔴 OpenUniStream error for player %v: %v", ws.session.RemoteAddr(), err)
+			return err
+		}
+		ws.sendStream = str
+	}
+
+	ws.sendStream.SetWriteDeadline(time.Now().Add(reliableWriteTimeout))
+	if err := writeFrame(ws.sendStream, data); err != nil {
+		log.Printf("🔴 SendReliable error for player %v: %v", ws.session.RemoteAddr(), err)
+		if !errors.Is(err, ErrMessageTooLarge) {
+			ws.sendStream.CancelWrite(0)
+			ws.sendStream = nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ReceiveReliable 接收客户端通过单向流发来的可靠消息
+// 客户端可以打开任意条单向流，同一条流内的消息保持顺序
+func (ws *WtSession) ReceiveReliable() ([]byte, error) {
+	if !ws.IsConnected() {
+		return nil, fmt.Errorf("session is nil")
+	}
+	ws.acceptOnce.Do(func() {
+		go ws.acceptStreams()
+	})
+	select {
+	case data := <-ws.reliableIn:
+		return data, nil
+	case <-ws.ctx.Done():
+		return nil, ws.ctx.Err()
+	case <-ws.session.Context().Done():
+		return nil, ws.session.Context().Err()
+	}
+}
+
+// acceptStreams 接受客户端打开的单向流，直到会话结束
+func (ws *WtSession) acceptStreams() {
+	for {
+		str, err := ws.session.AcceptUniStream(ws.ctx)
+		if err != nil {
+			return
+		}
+		go ws.readStream(str)
+	}
+}
+
+// readStream 逐条读出单向流中的消息，流结束或出错时返回
+func (ws *WtSession) readStream(str *webtransport.ReceiveStream) {
+	for {
+		data, err := readFrame(str)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ws.ctx.Err() == nil {
+				log.Printf("🔴 ReceiveReliable error for player %v: %v", ws.session.RemoteAddr(), err)
+				str.CancelRead(0)
+			}
+			return
+		}
+		select {
+		case ws.reliableIn <- data:
+		case <-ws.ctx.Done():
+			return
+		}
 	}
-	return data, err
 }
 
 func (ws *WtSession) GetRemoteAddr() net.Addr {
@@ -70,3 +224,34 @@ func (ws *WtSession) CloseWithError(code uint32, reason string) error {
 	}
 	return ws.session.CloseWithError(webtransport.SessionErrorCode(code), reason)
 }
+
+// CloseWithMessage 通过可靠通道发送最后一条消息并结束该流，在 closeLinger 后关闭会话
+// 可靠通道不可用时退回为数据报
+// 关闭在后台进行，不阻塞调用方（通常是客户端出站队列的写入 goroutine）
+func (ws *WtSession) CloseWithMessage(data []byte, code uint32, reason string) error {
+	if !ws.IsConnected() {
+		return fmt.Errorf("session is nil")
+	}
+	if !ws.closing.CompareAndSwap(false, true) {
+		return nil
+	}
+	ws.stop()
+
+	go func() {
+		if err := ws.writeReliable(data); err != nil {
+			if err := ws.session.SendDatagram(data); err != nil {
+				log.Printf("🔴 SendDatagram (final) error for player %v: %v", ws.session.RemoteAddr(), err)
+			}
+		} else {
+			// 结束流，使最后一条消息先于会话关闭送达
+			ws.sendMu.Lock()
+			if ws.sendStream != nil {
+				ws.sendStream.Close()
+			}
+			ws.sendMu.Unlock()
+		}
+		time.Sleep(closeLinger)
+		ws.session.CloseWithError(webtransport.SessionErrorCode(code), reason)
+	}()
+	return nil
+}
@@ -0,0 +1,56 @@
+package lockstep_sync
+
+import "sync"
+
+// ChecksumStore 环形缓冲区，保存最近若干帧的权威状态校验和
+// 帧号从 1 开始，超出容量时新帧覆盖最旧的帧
+type ChecksumStore struct {
+	mu        sync.RWMutex
+	frameIDs  []uint32 // 各槽位保存的帧号，0 为空槽位
+	checksums []uint64
+}
+
+// NewChecksumStore 创建一个最多保存 capacity 帧校验和的存储
+func NewChecksumStore(capacity uint32) *ChecksumStore {
+	if capacity == 0 {
+		capacity = 1
+	}
+	return &ChecksumStore{
+		frameIDs:  make([]uint32, capacity),
+		checksums: make([]uint64, capacity),
+	}
+}
+
+// Store 保存到达 frameID 帧后的权威状态校验和
+func (cs *ChecksumStore) Store(frameID uint32, checksum uint64) {
+	if frameID == 0 {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	slot := int(frameID % uint32(len(cs.frameIDs)))
+	cs.frameIDs[slot] = frameID
+	cs.checksums[slot] = checksum
+}
+
+// Get 获取 frameID 帧的权威状态校验和，已被覆盖或尚未保存时返回 false
+func (cs *ChecksumStore) Get(frameID uint32) (uint64, bool) {
+	if frameID == 0 {
+		return 0, false
+	}
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	slot := int(frameID % uint32(len(cs.frameIDs)))
+	if cs.frameIDs[slot] != frameID {
+		return 0, false
+	}
+	return cs.checksums[slot], true
+}
+
+// Clear 清空所有校验和
+func (cs *ChecksumStore) Clear() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	clear(cs.frameIDs)
+	clear(cs.checksums)
+}
@@ -0,0 +1,89 @@
+package lockstep_sync
+
+import (
+	"slices"
+	"sync"
+)
+
+// DefaultChunkID 默认的全局 chunk，未分 chunk 的游戏只使用此 chunk
+const DefaultChunkID = 0
+
+// ChunkSubscriptions 客户端订阅的 chunk 及各 chunk 开始接收的帧号
+// 新客户端默认只订阅 DefaultChunkID
+type ChunkSubscriptions struct {
+	mu sync.RWMutex
+	// chunkID -> 从该帧号开始接收此 chunk 的帧
+	since map[int]uint32
+}
+
+// NewChunkSubscriptions 创建只订阅了 DefaultChunkID 的订阅表
+func NewChunkSubscriptions() *ChunkSubscriptions {
+	cs := &ChunkSubscriptions{}
+	cs.Reset()
+	return cs
+}
+
+// Subscribe 订阅 chunkID，从 fromFrame 帧开始接收，已订阅时不变并返回 false
+func (cs *ChunkSubscriptions) Subscribe(chunkID int, fromFrame uint32) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if _, ok := cs.since[chunkID]; ok {
+		return false
+	}
+	cs.since[chunkID] = fromFrame
+	return true
+}
+
+// Unsubscribe 取消订阅 chunkID，未订阅时返回 false
+func (cs *ChunkSubscriptions) Unsubscribe(chunkID int) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if _, ok := cs.since[chunkID]; !ok {
+		return false
+	}
+	delete(cs.since, chunkID)
+	return true
+}
+
+// Since 获取开始接收 chunkID 的帧号，未订阅时返回 false
+func (cs *ChunkSubscriptions) Since(chunkID int) (uint32, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	since, ok := cs.since[chunkID]
+	return since, ok
+}
+
+// IDs 已订阅的 chunk，升序
+func (cs *ChunkSubscriptions) IDs() []int {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	ids := make([]int, 0, len(cs.since))
+	for id := range cs.since {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Len 已订阅的 chunk 数量
+func (cs *ChunkSubscriptions) Len() int {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return len(cs.since)
+}
+
+// Restart 保留已订阅的 chunk，并从下一场对局的第一帧开始接收
+func (cs *ChunkSubscriptions) Restart() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	for chunkID := range cs.since {
+		cs.since[chunkID] = 0
+	}
+}
+
+// Reset 恢复为只订阅 DefaultChunkID
+func (cs *ChunkSubscriptions) Reset() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.since = map[int]uint32{DefaultChunkID: 0}
+}
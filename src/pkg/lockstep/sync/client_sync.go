@@ -13,24 +13,103 @@ const (
 )
 
 type ClientSyncData struct {
-	ID uint32 // 用户 ID
+	ID    uint32        // 用户 ID
+	state atomic.Uint32 // 连接状态 EnumPlayerState
 	// 帧同步信息
 	LatestNextFrameID    atomic.Uint32 // 最近服务器获知的该用户所在的下一帧
 	LatestAckNextFrameID atomic.Uint32 // 最近该用户确认(ACK)的帧
+	LatestInputFrameID   atomic.Uint32 // 最近收到该用户提交输入的帧
+	Inputs               InputWindow   // 最近已收到输入的帧，用于对冗余发送的输入去重
+	SubmittedInputs      InputFrames   // 尚未步进且已收到输入的帧，悲观锁步据此判断是否可以步进
+
+	// 快照追帧信息
+	SentSnapshotFrameID atomic.Uint32 // 最近发送给该用户的快照帧号
+	SnapshotSentAtFrame atomic.Uint32 // 发送该快照时服务端的 NextFrameID
+
+	// 不同步检测信息
+	DesyncFrameID atomic.Uint32 // 检测到该用户不同步的帧号，0 为同步
+
+	// 订阅的 chunk，只接收这些 chunk 的帧数据
+	Chunks *ChunkSubscriptions
+
+	// 链路 RTT 与丢包率估计，用于调整帧的冗余发送
+	Link *LinkStats
 }
 
 func NewClientSyncData(id uint32) *ClientSyncData {
 	csd := &ClientSyncData{
-		ID: id,
+		ID:     id,
+		Chunks: NewChunkSubscriptions(),
+		Link:   NewLinkStats(),
 	}
+	csd.state.Store(uint32(PlayerStateConnected))
 	csd.LatestNextFrameID.Store(1)
 	csd.LatestAckNextFrameID.Store(0)
+	csd.LatestInputFrameID.Store(0)
 	return csd
 }
 
+// Reset 为下一场对局重置帧同步信息
+// 订阅的 chunk 被保留，并从下一场对局的第一帧开始接收
 func (pc *ClientSyncData) Reset() {
 	pc.LatestNextFrameID.Store(1)
 	pc.LatestAckNextFrameID.Store(0)
+	pc.LatestInputFrameID.Store(0)
+	pc.Inputs.Reset()
+	pc.SubmittedInputs.Reset()
+	pc.SentSnapshotFrameID.Store(0)
+	pc.SnapshotSentAtFrame.Store(0)
+	pc.DesyncFrameID.Store(0)
+	pc.Chunks.Restart()
+	pc.Link.Reset()
+}
+
+// ResetProgress 只重置帧进度与确认，例如回放回退时
+// 订阅的 chunk、链路估计与输入记录均被保留
+func (pc *ClientSyncData) ResetProgress() {
+	pc.LatestNextFrameID.Store(1)
+	pc.LatestAckNextFrameID.Store(0)
+	pc.SentSnapshotFrameID.Store(0)
+	pc.SnapshotSentAtFrame.Store(0)
+}
+
+// State 获取玩家连接状态
+func (pc *ClientSyncData) State() EnumPlayerState {
+	return EnumPlayerState(pc.state.Load())
+}
+
+// SetState 设置玩家连接状态
+func (pc *ClientSyncData) SetState(state EnumPlayerState) {
+	pc.state.Store(uint32(state))
+}
+
+// IsDisconnected 玩家是否处于断线等待重连状态
+func (pc *ClientSyncData) IsDisconnected() bool {
+	return pc.State() == PlayerStateDisconnected
+}
+
+// UpdateInputFrame 记录该用户已提交输入的帧号，只会向前推进
+func (pc *ClientSyncData) UpdateInputFrame(frameID uint32) {
+	for {
+		old := pc.LatestInputFrameID.Load()
+		if frameID <= old || pc.LatestInputFrameID.CompareAndSwap(old, frameID) {
+			return
+		}
+	}
+}
+
+// AdvanceAck 记录该用户确认的帧，只会向前推进
+// 乱序或迟到的数据报携带的旧 ack 被忽略，返回 ack 是否推进
+func (pc *ClientSyncData) AdvanceAck(ack uint32) bool {
+	for {
+		old := pc.LatestAckNextFrameID.Load()
+		if ack <= old {
+			return false
+		}
+		if pc.LatestAckNextFrameID.CompareAndSwap(old, ack) {
+			return true
+		}
+	}
 }
 
 // UpdatePlayerFrame 更新玩家的帧同步信息
@@ -0,0 +1,60 @@
+package lockstep_sync
+
+import "testing"
+
+func TestAdvanceAckIgnoresStaleAcks(t *testing.T) {
+	csd := NewClientSyncData(1)
+
+	if !csd.AdvanceAck(5) {
+		t.Fatal("ack 5 did not advance")
+	}
+	if csd.AdvanceAck(3) || csd.AdvanceAck(5) {
+		t.Fatal("stale ack advanced")
+	}
+	if got := csd.LatestAckNextFrameID.Load(); got != 5 {
+		t.Fatalf("ack = %d, want 5", got)
+	}
+
+	csd.Reset()
+	if !csd.AdvanceAck(2) {
+		t.Fatal("ack did not advance after reset")
+	}
+}
+
+func TestResetProgressKeepsSubscriptionsAndLink(t *testing.T) {
+	csd := NewClientSyncData(1)
+	csd.Chunks.Subscribe(3, 1)
+	csd.LatestNextFrameID.Store(50)
+	csd.AdvanceAck(40)
+	csd.SentSnapshotFrameID.Store(30)
+	csd.Inputs.Mark(45)
+
+	csd.ResetProgress()
+
+	if next, ack := csd.GetCurrentNextFrame(); next != 1 || ack != 0 {
+		t.Fatalf("progress = (%d, %d), want (1, 0)", next, ack)
+	}
+	if csd.SentSnapshotFrameID.Load() != 0 {
+		t.Fatal("sent snapshot frame not reset")
+	}
+	if _, ok := csd.Chunks.Since(3); !ok {
+		t.Fatal("chunk subscription dropped")
+	}
+	if !csd.Inputs.Has(45) {
+		t.Fatal("input window cleared")
+	}
+}
+
+func TestResetKeepsChunkSubscriptionsForNextMatch(t *testing.T) {
+	csd := NewClientSyncData(1)
+	csd.Chunks.Subscribe(3, 500)
+
+	csd.Reset()
+
+	if since, ok := csd.Chunks.Since(3); !ok || since != 0 {
+		t.Fatalf("chunk 3 subscription = (%d, %v), want (0, true)", since, ok)
+	}
+	if _, ok := csd.Chunks.Since(DefaultChunkID); !ok {
+		t.Fatal("default chunk subscription dropped")
+	}
+}
@@ -0,0 +1,206 @@
+package lockstep_sync
+
+import (
+	"lockstep-core/src/pkg/lockstep/world"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// FrameStore 以环形缓冲区保存最近的帧数据
+// 帧号 frameID 存放于 frameID % capacity 的槽位，
+// 当缓冲区写满时，新帧会覆盖最旧的帧
+type FrameStore struct {
+	mu sync.RWMutex
+
+	frames []*world.FrameData
+	ids    []uint32 // 槽位中帧的帧号，0 表示空槽位
+	sizes  []int    // 槽位中帧的序列化大小
+
+	count   int    // 当前保存的帧数量
+	bytes   int    // 当前保存的帧序列化总大小
+	oldest  uint32 // 当前保存的最旧帧号，0 表示为空
+	newest  uint32 // 当前保存的最新帧号，0 表示为空
+	evicted uint64 // 累计被淘汰的帧数量
+}
+
+// NewFrameStore 创建一个最多保存 capacity 帧的环形缓冲区
+func NewFrameStore(capacity uint32) *FrameStore {
+	if capacity == 0 {
+		capacity = 1
+	}
+	return &FrameStore{
+		frames: make([]*world.FrameData, capacity),
+		ids:    make([]uint32, capacity),
+		sizes:  make([]int, capacity),
+	}
+}
+
+// Capacity 最多保存的帧数量
+func (fs *FrameStore) Capacity() int {
+	return len(fs.frames)
+}
+
+// Store 保存一帧数据，若槽位被旧帧占用则淘汰旧帧
+func (fs *FrameStore) Store(frameID uint32, frameData *world.FrameData) {
+	if frameID == 0 {
+		return
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	capacity := uint32(len(fs.frames))
+	if fs.newest >= capacity && frameID <= fs.newest-capacity {
+		// 早于缓冲区窗口的帧不再保存
+		return
+	}
+
+	slot := fs.slot(frameID)
+	if fs.ids[slot] != 0 {
+		if fs.ids[slot] != frameID {
+			fs.evicted++
+		}
+		fs.removeSlot(slot)
+	}
+
+	size := proto.Size(frameData)
+	fs.frames[slot] = frameData
+	fs.ids[slot] = frameID
+	fs.sizes[slot] = size
+	fs.count++
+	fs.bytes += size
+
+	if fs.count == 1 {
+		fs.oldest, fs.newest = frameID, frameID
+		return
+	}
+	if frameID > fs.newest {
+		fs.newest = frameID
+	}
+	if frameID < fs.oldest {
+		fs.oldest = frameID
+	} else if fs.ids[fs.slot(fs.oldest)] != fs.oldest {
+		// 最旧帧已被覆盖
+		fs.recalcOldest()
+	}
+}
+
+// Get 获取指定帧号的帧数据，已淘汰或尚未保存的帧返回 false
+func (fs *FrameStore) Get(frameID uint32) (*world.FrameData, bool) {
+	if frameID == 0 {
+		return nil, false
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	slot := fs.slot(frameID)
+	if fs.ids[slot] != frameID {
+		return nil, false
+	}
+	return fs.frames[slot], true
+}
+
+// PruneThrough 淘汰帧号小于等于 frameID 的所有帧，返回淘汰数量
+func (fs *FrameStore) PruneThrough(frameID uint32) int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.count == 0 || frameID < fs.oldest {
+		return 0
+	}
+	upper := frameID
+	if upper > fs.newest {
+		upper = fs.newest
+	}
+
+	pruned := 0
+	for id := fs.oldest; id <= upper; id++ {
+		slot := fs.slot(id)
+		if fs.ids[slot] == id {
+			fs.removeSlot(slot)
+			pruned++
+		}
+	}
+	fs.evicted += uint64(pruned)
+	if fs.count == 0 {
+		fs.oldest, fs.newest = 0, 0
+	} else {
+		fs.recalcOldest()
+	}
+	return pruned
+}
+
+// Clear 清空所有帧
+func (fs *FrameStore) Clear() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for i := range fs.frames {
+		fs.frames[i] = nil
+		fs.ids[i] = 0
+		fs.sizes[i] = 0
+	}
+	fs.count, fs.bytes = 0, 0
+	fs.oldest, fs.newest = 0, 0
+}
+
+// Len 当前保存的帧数量
+func (fs *FrameStore) Len() int {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.count
+}
+
+// Bytes 当前保存的帧序列化总大小
+func (fs *FrameStore) Bytes() int {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.bytes
+}
+
+// Range 获取当前保存的最旧与最新帧号，为空时均为 0
+func (fs *FrameStore) Range() (oldest, newest uint32) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.oldest, fs.newest
+}
+
+// Evicted 累计被淘汰的帧数量
+func (fs *FrameStore) Evicted() uint64 {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.evicted
+}
+
+func (fs *FrameStore) slot(frameID uint32) int {
+	return int(frameID % uint32(len(fs.frames)))
+}
+
+// removeSlot 清空槽位，调用者需持有写锁
+func (fs *FrameStore) removeSlot(slot int) {
+	fs.count--
+	fs.bytes -= fs.sizes[slot]
+	fs.frames[slot] = nil
+	fs.ids[slot] = 0
+	fs.sizes[slot] = 0
+}
+
+// recalcOldest 重新计算最旧帧号，调用者需持有写锁
+// 有效帧一定落在 (newest - capacity, newest] 区间内
+func (fs *FrameStore) recalcOldest() {
+	capacity := uint32(len(fs.frames))
+	start := uint32(1)
+	if fs.newest > capacity {
+		start = fs.newest - capacity + 1
+	}
+	if fs.oldest > start {
+		start = fs.oldest
+	}
+	for id := start; id <= fs.newest; id++ {
+		if fs.ids[fs.slot(id)] == id {
+			fs.oldest = id
+			return
+		}
+	}
+	fs.oldest = 0
+}
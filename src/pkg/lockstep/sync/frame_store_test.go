@@ -0,0 +1,76 @@
+package lockstep_sync
+
+import (
+	"lockstep-core/src/pkg/lockstep/world"
+	"testing"
+)
+
+func storeFrames(fs *FrameStore, from, to uint32) {
+	for id := from; id <= to; id++ {
+		fs.Store(id, &world.FrameData{FrameId: id})
+	}
+}
+
+func TestFrameStoreWrapsAround(t *testing.T) {
+	fs := NewFrameStore(4)
+	storeFrames(fs, 1, 6)
+
+	if oldest, newest := fs.Range(); oldest != 3 || newest != 6 {
+		t.Fatalf("Range = (%d, %d), want (3, 6)", oldest, newest)
+	}
+	if fs.Len() != 4 || fs.Evicted() != 2 {
+		t.Fatalf("Len = %d, Evicted = %d, want 4 and 2", fs.Len(), fs.Evicted())
+	}
+	for id := uint32(1); id <= 2; id++ {
+		if _, ok := fs.Get(id); ok {
+			t.Errorf("overwritten frame %d is still returned", id)
+		}
+	}
+	for id := uint32(3); id <= 6; id++ {
+		frame, ok := fs.Get(id)
+		if !ok || frame.GetFrameId() != id {
+			t.Errorf("Get(%d) = %v, %v", id, frame, ok)
+		}
+	}
+
+	// 早于窗口的帧不再保存，也不会覆盖窗口内的帧
+	fs.Store(2, &world.FrameData{FrameId: 2})
+	if _, ok := fs.Get(6); !ok {
+		t.Fatal("storing a stale frame overwrote frame 6")
+	}
+	if _, ok := fs.Get(2); ok {
+		t.Fatal("stale frame was stored")
+	}
+}
+
+func TestFrameStorePruneThrough(t *testing.T) {
+	fs := NewFrameStore(8)
+	storeFrames(fs, 1, 5)
+	bytes := fs.Bytes()
+
+	if pruned := fs.PruneThrough(3); pruned != 3 {
+		t.Fatalf("PruneThrough(3) = %d, want 3", pruned)
+	}
+	if oldest, newest := fs.Range(); oldest != 4 || newest != 5 {
+		t.Fatalf("Range = (%d, %d), want (4, 5)", oldest, newest)
+	}
+	if fs.Len() != 2 || fs.Bytes() >= bytes {
+		t.Fatalf("Len = %d, Bytes = %d (was %d)", fs.Len(), fs.Bytes(), bytes)
+	}
+	if pruned := fs.PruneThrough(2); pruned != 0 {
+		t.Fatalf("pruning already pruned frames removed %d", pruned)
+	}
+
+	if pruned := fs.PruneThrough(100); pruned != 2 {
+		t.Fatalf("PruneThrough(100) = %d, want 2", pruned)
+	}
+	if oldest, newest := fs.Range(); oldest != 0 || newest != 0 || fs.Len() != 0 || fs.Bytes() != 0 {
+		t.Fatalf("store is not empty after pruning everything: range (%d, %d), %d frames, %d bytes",
+			oldest, newest, fs.Len(), fs.Bytes())
+	}
+
+	storeFrames(fs, 6, 7)
+	if oldest, newest := fs.Range(); oldest != 6 || newest != 7 {
+		t.Fatalf("Range after refill = (%d, %d), want (6, 7)", oldest, newest)
+	}
+}
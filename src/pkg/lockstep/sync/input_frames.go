@@ -0,0 +1,41 @@
+package lockstep_sync
+
+// InputFrames 记录已提交输入的每一个帧号，悲观锁步据此判断某一帧是否已收到该用户的输入
+// 与 InputWindow 不同，早于窗口或跳过的帧不会被视为已收到
+// 帧步进后由 PruneThrough 淘汰，仅在房间主循环中访问
+type InputFrames struct {
+	frames map[uint32]struct{}
+}
+
+// Mark 记录已收到 frameID 帧的输入
+func (f *InputFrames) Mark(frameID uint32) {
+	if f.frames == nil {
+		f.frames = make(map[uint32]struct{})
+	}
+	f.frames[frameID] = struct{}{}
+}
+
+// Has 是否已收到 frameID 帧的输入
+func (f *InputFrames) Has(frameID uint32) bool {
+	_, ok := f.frames[frameID]
+	return ok
+}
+
+// PruneThrough 淘汰帧号小于等于 frameID 的记录
+func (f *InputFrames) PruneThrough(frameID uint32) {
+	for id := range f.frames {
+		if id <= frameID {
+			delete(f.frames, id)
+		}
+	}
+}
+
+// Len 记录的帧数量
+func (f *InputFrames) Len() int {
+	return len(f.frames)
+}
+
+// Reset 清空所有记录
+func (f *InputFrames) Reset() {
+	clear(f.frames)
+}
@@ -0,0 +1,48 @@
+package lockstep_sync
+
+// InputWindowSize 输入去重窗口覆盖的帧数
+const InputWindowSize = 64
+
+// InputWindow 记录最近 InputWindowSize 帧内已收到输入的帧号，用于对冗余发送的输入去重
+// 早于窗口的帧视为已收到
+// 仅在房间主循环中访问
+type InputWindow struct {
+	latest uint32 // 已收到输入的最新帧号
+	mask   uint64 // 第 i 位表示 latest-i 帧的输入已收到
+}
+
+// Has 是否已收到 frameID 帧的输入
+func (w *InputWindow) Has(frameID uint32) bool {
+	if frameID > w.latest {
+		return false
+	}
+	offset := w.latest - frameID
+	if offset >= InputWindowSize {
+		return true
+	}
+	return w.mask&(uint64(1)<<offset) != 0
+}
+
+// Mark 记录已收到 frameID 帧的输入
+func (w *InputWindow) Mark(frameID uint32) {
+	if frameID > w.latest {
+		shift := frameID - w.latest
+		if shift >= InputWindowSize {
+			w.mask = 0
+		} else {
+			w.mask <<= shift
+		}
+		w.latest = frameID
+		w.mask |= 1
+		return
+	}
+	if offset := w.latest - frameID; offset < InputWindowSize {
+		w.mask |= uint64(1) << offset
+	}
+}
+
+// Reset 清空窗口
+func (w *InputWindow) Reset() {
+	w.latest = 0
+	w.mask = 0
+}
@@ -0,0 +1,169 @@
+package lockstep_sync
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// linkWindowSize 记录发送时间的最近帧数量
+	linkWindowSize = 1024
+	// initialRTO 尚无 RTT 样本时的重传超时
+	initialRTO = 250 * time.Millisecond
+	// initialLoss 尚无样本时假定的丢包率
+	initialLoss = 0.1
+	// targetResidualLoss 冗余发送后期望的帧丢失率
+	targetResidualLoss = 0.01
+)
+
+// LinkStats 根据 ack 的时机估计客户端链路的 RTT 与丢包率，用于调整帧的冗余发送
+// 每帧首次发送的时间到该帧被 ack 的时间为一个 RTT 样本；
+// 晚于 RTT 估计值明显到达的 ack 视为该帧的首次发送已丢失
+type LinkStats struct {
+	mu sync.Mutex
+
+	// 按帧号记录的首次与最近一次发送时间，帧号存放于 frameID % linkWindowSize 的槽位
+	frameIDs  [linkWindowSize]uint32
+	firstSent [linkWindowSize]time.Time
+	lastSent  [linkWindowSize]time.Time
+
+	// 平滑 RTT 与 RTT 偏差，算法同 TCP (RFC 6298)
+	srtt   time.Duration
+	rttvar time.Duration
+	// 丢包率的指数移动平均
+	loss float64
+	// 最近处理过的 ack
+	lastAck uint32
+}
+
+// NewLinkStats 创建链路估计
+func NewLinkStats() *LinkStats {
+	return &LinkStats{loss: initialLoss}
+}
+
+// Reset 清空所有样本
+func (ls *LinkStats) Reset() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.frameIDs = [linkWindowSize]uint32{}
+	ls.firstSent = [linkWindowSize]time.Time{}
+	ls.lastSent = [linkWindowSize]time.Time{}
+	ls.srtt, ls.rttvar = 0, 0
+	ls.loss = initialLoss
+	ls.lastAck = 0
+}
+
+// OnSend 记录 frameID 帧在 now 被发送
+func (ls *LinkStats) OnSend(frameID uint32, now time.Time) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	slot := frameID % linkWindowSize
+	if ls.frameIDs[slot] != frameID {
+		ls.frameIDs[slot] = frameID
+		ls.firstSent[slot] = now
+	}
+	ls.lastSent[slot] = now
+}
+
+// LastSent 获取 frameID 帧最近一次发送的时间，未发送过时返回 false
+func (ls *LinkStats) LastSent(frameID uint32) (time.Time, bool) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	slot := frameID % linkWindowSize
+	if ls.frameIDs[slot] != frameID {
+		return time.Time{}, false
+	}
+	return ls.lastSent[slot], true
+}
+
+// OnAck 客户端在 now 确认了直到 ack 的帧，据此更新 RTT 与丢包率
+func (ls *LinkStats) OnAck(ack uint32, now time.Time) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ack <= ls.lastAck {
+		if ack < ls.lastAck {
+			// 回放回退等情况下 ack 被重置
+			ls.lastAck = ack
+		}
+		return
+	}
+	from := ls.lastAck + 1
+	ls.lastAck = ack
+	if ack-from >= linkWindowSize {
+		from = ack - linkWindowSize + 1
+	}
+
+	// 以最新确认帧的首次发送时间计算 RTT 样本
+	if slot := ack % linkWindowSize; ls.frameIDs[slot] == ack {
+		ls.addRTTSample(now.Sub(ls.firstSent[slot]))
+	}
+	// 晚于 srtt + 2*rttvar 才确认的帧视为首次发送已丢失
+	lateBound := ls.srtt + 2*ls.rttvar
+	for frameID := from; frameID <= ack; frameID++ {
+		slot := frameID % linkWindowSize
+		if ls.frameIDs[slot] != frameID {
+			continue
+		}
+		sample := 0.0
+		if ls.srtt > 0 && now.Sub(ls.firstSent[slot]) > lateBound {
+			sample = 1
+		}
+		ls.loss += (sample - ls.loss) / 16
+	}
+}
+
+// addRTTSample 按 RFC 6298 更新 srtt 与 rttvar
+func (ls *LinkStats) addRTTSample(rtt time.Duration) {
+	if rtt <= 0 {
+		return
+	}
+	if ls.srtt == 0 {
+		ls.srtt = rtt
+		ls.rttvar = rtt / 2
+		return
+	}
+	diff := ls.srtt - rtt
+	if diff < 0 {
+		diff = -diff
+	}
+	ls.rttvar = (3*ls.rttvar + diff) / 4
+	ls.srtt = (7*ls.srtt + rtt) / 8
+}
+
+// RTT 平滑后的 RTT，尚无样本时为 0
+func (ls *LinkStats) RTT() time.Duration {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.srtt
+}
+
+// Loss 估计的丢包率
+func (ls *LinkStats) Loss() float64 {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.loss
+}
+
+// RTO 重传超时，未被确认的帧在上次发送后超过此时间需要重发
+func (ls *LinkStats) RTO() time.Duration {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.srtt == 0 {
+		return initialRTO
+	}
+	return ls.srtt + 4*ls.rttvar
+}
+
+// Redundancy 每一帧需要在连续多少次发送中重复携带，使丢失率低于 targetResidualLoss
+// 结果限制在 [1, maxRedundancy] 之间
+func (ls *LinkStats) Redundancy(maxRedundancy int) int {
+	loss := ls.Loss()
+	redundancy := maxRedundancy
+	if loss <= 0 {
+		redundancy = 1
+	} else if loss < 1 {
+		redundancy = int(math.Ceil(math.Log(targetResidualLoss) / math.Log(loss)))
+	}
+	return max(1, min(redundancy, maxRedundancy))
+}
@@ -2,9 +2,8 @@ package lockstep_sync
 
 import (
 	"lockstep-core/src/pkg/lockstep/world"
+	"sync"
 	"sync/atomic"
-
-	"github.com/alphadose/haxmap"
 )
 
 type ServerSyncData struct {
@@ -16,36 +15,126 @@ type ServerSyncData struct {
 	// stored data
 
 	// frameDatas
-	FrameDatas *haxmap.Map[uint32, *world.FrameData]
+	// 环形缓冲区，只保留有限数量的历史帧
+	// 即默认 chunk DefaultChunkID 的帧
+	FrameDatas *FrameStore
+	// 其他 chunk 的帧，chunkID -> 帧，仅在有客户端订阅时存在
+	chunkFrames map[int]*FrameStore
+	chunkMu     sync.RWMutex
+	// 每个 chunk 最多保留的历史帧数量
+	frameHistorySize uint32
 
 	// snapshots
-	Snapshots *haxmap.Map[uint32, world.Snapshot]
+	// 只保留最近的若干个快照
+	Snapshots *SnapshotStore
+
+	// checksums
+	// 游戏世界提供的权威状态校验和，与帧历史保留相同数量的帧
+	Checksums *ChecksumStore
+
+	// 可选的帧数据接收者，例如回放录制
+	sink FrameSink
+}
 
-	// 默认全局chunkID=0
-	// FUTURE: 未来改为 map shardedslice 以支持多chunk
+// FrameSink 接收所有被保存的帧与快照，例如用于录制回放
+// 只接收默认 chunk DefaultChunkID 的帧，其他 chunk 的帧与其帧号相同，不会被转发
+// 调用发生在房间主循环中，实现不应长时间阻塞
+type FrameSink interface {
+	OnStoreFrame(frameID uint32, frameData *world.FrameData)
+	OnStoreSnapshot(frameID uint32, snapshot world.Snapshot)
 }
 
-func NewServerSyncData() *ServerSyncData {
+// SyncStats 帧历史的内存占用统计，用于监控
+type SyncStats struct {
+	NextFrameID   uint32 `json:"next_frame_id"`
+	FrameCount    int    `json:"frame_count"`    // 保存的帧数量
+	FrameCapacity int    `json:"frame_capacity"` // 最多保存的帧数量
+	FrameBytes    int    `json:"frame_bytes"`    // 保存的帧序列化总大小
+	OldestFrameID uint32 `json:"oldest_frame_id"`
+	NewestFrameID uint32 `json:"newest_frame_id"`
+	EvictedFrames uint64 `json:"evicted_frames"` // 累计淘汰的帧数量
+	SnapshotCount int    `json:"snapshot_count"` // 保存的快照数量
+	SnapshotBytes int    `json:"snapshot_bytes"` // 保存的快照总大小
+	ChunkCount    int    `json:"chunk_count"`    // 默认 chunk 以外保存了帧的 chunk 数量
+	ChunkBytes    int    `json:"chunk_bytes"`    // 默认 chunk 以外的帧序列化总大小
+}
+
+// NewServerSyncData 创建帧同步数据
+// frameHistorySize : 最多保留的历史帧数量
+// snapshotHistorySize : 最多保留的快照数量
+func NewServerSyncData(frameHistorySize, snapshotHistorySize uint32) *ServerSyncData {
 	nextRenderFrame := &atomic.Uint32{}
-	nextRenderFrame.Store(1)      // 下一帧渲染为 1，当前都在 0
-	const initFrameNum = 30 * 128 // 初始化30s的缓冲
+	nextRenderFrame.Store(1) // 下一帧渲染为 1，当前都在 0
 	return &ServerSyncData{
-		NextFrameID: nextRenderFrame,
-		FrameDatas:  haxmap.New[uint32, *world.FrameData](initFrameNum),
-		Snapshots:   haxmap.New[uint32, world.Snapshot](initFrameNum),
+		NextFrameID:      nextRenderFrame,
+		FrameDatas:       NewFrameStore(frameHistorySize),
+		chunkFrames:      make(map[int]*FrameStore),
+		frameHistorySize: frameHistorySize,
+		Snapshots:        NewSnapshotStore(snapshotHistorySize),
+		Checksums:        NewChecksumStore(frameHistorySize),
 	}
 }
 
+// Reset 重置帧 ID 并清空上一场游戏的帧与快照
 func (ssd *ServerSyncData) Reset() {
 	ssd.NextFrameID.Store(1) // 重置帧 ID 为 1
+	ssd.FrameDatas.Clear()
+	ssd.chunkMu.Lock()
+	ssd.chunkFrames = make(map[int]*FrameStore)
+	ssd.chunkMu.Unlock()
+	ssd.Snapshots.Clear()
+	ssd.Checksums.Clear()
+}
+
+// SetSink 设置帧数据接收者，nil 为取消
+func (ssd *ServerSyncData) SetSink(sink FrameSink) {
+	ssd.sink = sink
 }
 
 func (ssd *ServerSyncData) StoreFrame(frameID uint32, frameData *world.FrameData) {
-	ssd.FrameDatas.Set(frameID, frameData)
+	ssd.FrameDatas.Store(frameID, frameData)
+	if ssd.sink != nil {
+		ssd.sink.OnStoreFrame(frameID, frameData)
+	}
+}
+
+// StoreChunkFrame 保存某个 chunk 的帧数据，DefaultChunkID 等同于 StoreFrame
+// 其他 chunk 的帧不会转发给 FrameSink
+func (ssd *ServerSyncData) StoreChunkFrame(chunkID int, frameID uint32, frameData *world.FrameData) {
+	if chunkID == DefaultChunkID {
+		ssd.StoreFrame(frameID, frameData)
+		return
+	}
+	ssd.chunkMu.Lock()
+	store, ok := ssd.chunkFrames[chunkID]
+	if !ok {
+		store = NewFrameStore(ssd.frameHistorySize)
+		ssd.chunkFrames[chunkID] = store
+	}
+	ssd.chunkMu.Unlock()
+
+	store.Store(frameID, frameData)
+}
+
+// GetChunkFrame 获取某个 chunk 的帧数据
+func (ssd *ServerSyncData) GetChunkFrame(chunkID int, frameID uint32) (*world.FrameData, bool) {
+	if chunkID == DefaultChunkID {
+		return ssd.GetFrame(frameID)
+	}
+	ssd.chunkMu.RLock()
+	store, ok := ssd.chunkFrames[chunkID]
+	ssd.chunkMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return store.Get(frameID)
 }
 
 func (ssd *ServerSyncData) StoreSnapshot(frameID uint32, snapshot world.Snapshot) {
-	ssd.Snapshots.Set(frameID, snapshot)
+	ssd.Snapshots.Store(frameID, snapshot)
+	if ssd.sink != nil {
+		ssd.sink.OnStoreSnapshot(frameID, snapshot)
+	}
 }
 
 func (ssd *ServerSyncData) GetFrame(frameID uint32) (*world.FrameData, bool) {
@@ -55,3 +144,53 @@ func (ssd *ServerSyncData) GetFrame(frameID uint32) (*world.FrameData, bool) {
 func (ssd *ServerSyncData) GetSnapshot(frameID uint32) (world.Snapshot, bool) {
 	return ssd.Snapshots.Get(frameID)
 }
+
+// Prune 淘汰不再需要的历史帧
+// oldestAck : 所有客户端中最旧的 ack 帧号，帧号不大于它的帧已被所有客户端确认
+// 若存在快照，则还需要保留最新快照之后的帧以供追帧，
+// 因此只淘汰同时早于最旧 ack 与最新快照的帧
+func (ssd *ServerSyncData) Prune(oldestAck uint32) int {
+	bound := oldestAck
+	if snapshotFrame, _, ok := ssd.Snapshots.Latest(); ok && snapshotFrame < bound {
+		bound = snapshotFrame
+	}
+	if bound == 0 {
+		return 0
+	}
+	pruned := ssd.FrameDatas.PruneThrough(bound)
+
+	ssd.chunkMu.Lock()
+	defer ssd.chunkMu.Unlock()
+	for chunkID, store := range ssd.chunkFrames {
+		pruned += store.PruneThrough(bound)
+		if store.Len() == 0 {
+			// 已无人订阅的 chunk 不再产生新帧，释放其缓冲区
+			delete(ssd.chunkFrames, chunkID)
+		}
+	}
+	return pruned
+}
+
+// Stats 获取帧历史的内存占用统计
+func (ssd *ServerSyncData) Stats() SyncStats {
+	oldest, newest := ssd.FrameDatas.Range()
+	ssd.chunkMu.RLock()
+	chunkCount, chunkBytes := len(ssd.chunkFrames), 0
+	for _, store := range ssd.chunkFrames {
+		chunkBytes += store.Bytes()
+	}
+	ssd.chunkMu.RUnlock()
+	return SyncStats{
+		NextFrameID:   ssd.NextFrameID.Load(),
+		FrameCount:    ssd.FrameDatas.Len(),
+		FrameCapacity: ssd.FrameDatas.Capacity(),
+		FrameBytes:    ssd.FrameDatas.Bytes(),
+		OldestFrameID: oldest,
+		NewestFrameID: newest,
+		EvictedFrames: ssd.FrameDatas.Evicted(),
+		SnapshotCount: ssd.Snapshots.Len(),
+		SnapshotBytes: ssd.Snapshots.Bytes(),
+		ChunkCount:    chunkCount,
+		ChunkBytes:    chunkBytes,
+	}
+}
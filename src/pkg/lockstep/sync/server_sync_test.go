@@ -0,0 +1,35 @@
+package lockstep_sync
+
+import (
+	"lockstep-core/src/pkg/lockstep/world"
+	"testing"
+)
+
+// recordingSink 记录收到的帧号
+type recordingSink struct {
+	frames []uint32
+}
+
+func (s *recordingSink) OnStoreFrame(frameID uint32, frameData *world.FrameData) {
+	s.frames = append(s.frames, frameID)
+}
+
+func (s *recordingSink) OnStoreSnapshot(frameID uint32, snapshot world.Snapshot) {}
+
+func TestSinkReceivesOnlyDefaultChunkFrames(t *testing.T) {
+	ssd := NewServerSyncData(8, 1)
+	sink := &recordingSink{}
+	ssd.SetSink(sink)
+
+	ssd.StoreFrame(1, &world.FrameData{FrameId: 1})
+	ssd.StoreChunkFrame(3, 1, &world.FrameData{FrameId: 1})
+	ssd.StoreChunkFrame(DefaultChunkID, 2, &world.FrameData{FrameId: 2})
+	ssd.StoreChunkFrame(3, 2, &world.FrameData{FrameId: 2})
+
+	if len(sink.frames) != 2 || sink.frames[0] != 1 || sink.frames[1] != 2 {
+		t.Fatalf("sink frames = %v, want [1 2]", sink.frames)
+	}
+	if _, ok := ssd.GetChunkFrame(3, 2); !ok {
+		t.Fatalf("chunk 3 frame 2 not stored")
+	}
+}
@@ -0,0 +1,128 @@
+package lockstep_sync
+
+import (
+	"lockstep-core/src/pkg/lockstep/world"
+	"sync"
+)
+
+type snapshotEntry struct {
+	frameID  uint32
+	snapshot world.Snapshot
+}
+
+// SnapshotStore 按帧号顺序保存最近的若干个状态快照
+// 超出容量时淘汰最旧的快照
+type SnapshotStore struct {
+	mu       sync.RWMutex
+	capacity int
+	entries  []snapshotEntry // 按帧号升序
+	bytes    int
+}
+
+// NewSnapshotStore 创建一个最多保存 capacity 个快照的存储
+func NewSnapshotStore(capacity uint32) *SnapshotStore {
+	if capacity == 0 {
+		capacity = 1
+	}
+	return &SnapshotStore{
+		capacity: int(capacity),
+		entries:  make([]snapshotEntry, 0, capacity),
+	}
+}
+
+// Store 保存 frameID 帧步进完成后的快照，相同帧号的快照会被替换
+func (ss *SnapshotStore) Store(frameID uint32, snapshot world.Snapshot) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := len(ss.entries)
+	for i > 0 && ss.entries[i-1].frameID >= frameID {
+		i--
+	}
+	if i < len(ss.entries) && ss.entries[i].frameID == frameID {
+		ss.bytes += len(snapshot) - len(ss.entries[i].snapshot)
+		ss.entries[i].snapshot = snapshot
+		return
+	}
+
+	ss.entries = append(ss.entries, snapshotEntry{})
+	copy(ss.entries[i+1:], ss.entries[i:])
+	ss.entries[i] = snapshotEntry{frameID: frameID, snapshot: snapshot}
+	ss.bytes += len(snapshot)
+
+	for len(ss.entries) > ss.capacity {
+		ss.bytes -= len(ss.entries[0].snapshot)
+		ss.entries = ss.entries[1:]
+	}
+}
+
+// Range 按帧号升序遍历保存的快照，f 返回 false 时停止
+func (ss *SnapshotStore) Range(f func(frameID uint32, snapshot world.Snapshot) bool) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	for _, entry := range ss.entries {
+		if !f(entry.frameID, entry.snapshot) {
+			return
+		}
+	}
+}
+
+// Get 获取指定帧号的快照
+func (ss *SnapshotStore) Get(frameID uint32) (world.Snapshot, bool) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	for _, e := range ss.entries {
+		if e.frameID == frameID {
+			return e.snapshot, true
+		}
+	}
+	return nil, false
+}
+
+// Latest 获取最新的快照及其帧号
+func (ss *SnapshotStore) Latest() (uint32, world.Snapshot, bool) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	if len(ss.entries) == 0 {
+		return 0, nil, false
+	}
+	e := ss.entries[len(ss.entries)-1]
+	return e.frameID, e.snapshot, true
+}
+
+// LatestAtOrBefore 获取帧号不大于 frameID 的最新快照及其帧号
+func (ss *SnapshotStore) LatestAtOrBefore(frameID uint32) (uint32, world.Snapshot, bool) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	for i := len(ss.entries) - 1; i >= 0; i-- {
+		if e := ss.entries[i]; e.frameID <= frameID {
+			return e.frameID, e.snapshot, true
+		}
+	}
+	return 0, nil, false
+}
+
+// Clear 清空所有快照
+func (ss *SnapshotStore) Clear() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.entries = ss.entries[:0]
+	ss.bytes = 0
+}
+
+// Len 当前保存的快照数量
+func (ss *SnapshotStore) Len() int {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return len(ss.entries)
+}
+
+// Bytes 当前保存的快照总大小
+func (ss *SnapshotStore) Bytes() int {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.bytes
+}
@@ -29,17 +29,27 @@ type IRoomContext interface {
 	// 返回 IPlayer 接口切片，只暴露核心信息（如UID）
 	GetAllPlayers() []uint32
 
+	// GetOwner 获取房主的玩家 ID，房间内没有玩家时返回 false
+	// 房主为第一个加入的玩家，房主离开后自动转移给其他玩家
+	GetOwner() (uint32, bool)
+
 	// GetNextFrame 获取步进到的下一 lockstep 的帧号
 	// 这是与 SyncData 交互的最关键部分
 	GetNextFrame() uint32
 
 	// # 动作请求
 
+	// DumpFrameHistory 将当前或上一场对局的帧与快照写入 dir 下的回放文件，返回文件路径
+	// 配置了回放目录时包含从开始至今的所有帧，否则只包含尚未淘汰的帧历史
+	// 例如在 OnHandleEndGame 中调用以保存本场对局，之后可通过 RoomManager.CreateReplayRoom 播放
+	DumpFrameHistory(dir string) (string, error)
+
 	// KickPlayer 请求核心框架踢掉一个玩家
-	// 游戏逻辑判断“为什么”踢，核心框架执行“如何”踢（关闭连接、清理资源等）
+	// 游戏逻辑判断“为什么”踢，核心框架执行“如何”踢（发送 ResponseKicked、关闭连接、清理资源等）
 	KickPlayer(uid uint32, reason string)
 
 	// DestroyRoom 请求核心框架销毁当前房间
 	// 例如，游戏逻辑在 Tick() 中判断出胜负已分，可以调用此方法来结束游戏
+	// 剩余客户端会收到原因为 CLOSE_REASON_GAME_ENDED 的 ResponseRoomClosed
 	DestroyRoom()
 }
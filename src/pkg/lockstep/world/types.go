@@ -16,3 +16,24 @@ type WorldEventData = messages.WorldEventData
 type FrameData = messages.FrameData
 
 type Snapshot = []byte
+
+// ReadyTimeoutPolicy 准备倒计时结束时如何处理仍未准备的玩家
+type ReadyTimeoutPolicy int
+
+const (
+	// ReadyTimeoutAutoReady 将未准备的玩家视为已准备
+	ReadyTimeoutAutoReady ReadyTimeoutPolicy = iota
+	// ReadyTimeoutKick 将未准备的玩家踢出房间
+	ReadyTimeoutKick
+)
+
+// LoadingTimeoutPolicy 加载超时时如何处理仍未加载完毕的玩家
+type LoadingTimeoutPolicy int
+
+const (
+	// LoadingTimeoutStartWithout 不再等待，直接开始游戏
+	// 未加载完毕的玩家仍在房间中，加载完毕前不参与锁步的等待，加载完毕后通过快照或历史帧追帧
+	LoadingTimeoutStartWithout LoadingTimeoutPolicy = iota
+	// LoadingTimeoutKick 将未加载完毕的玩家踢出房间
+	LoadingTimeoutKick
+)
@@ -1,5 +1,10 @@
 package world
 
+import (
+	"lockstep-core/src/constants"
+	"time"
+)
+
 // IGameWorld 是需要由具体游戏工程实现的接口
 // 需要外部调用时实现游戏世界生命周期
 // 核心框架的 Room 将会调用这些方法
@@ -17,15 +22,35 @@ type IGameWorld interface {
 	// OnPlayerLeave 当有玩家离开时调用
 	OnPlayerLeave(uid uint32)
 
+	// OnSpectatorJoin 当有观战者加入房间时调用，返回发送给该观战者的额外数据
+	// 观战者 ID 不小于 room.SpectatorIDBase，只接收延迟后的帧，不能提交输入
+	OnSpectatorJoin(uid uint32) (extraData []byte)
+
+	// OnSpectatorLeave 当有观战者离开时调用
+	OnSpectatorLeave(uid uint32)
+
 	// OnHandleInLobby 当有玩家在大厅状态下发送数据时调用
 	OnHandleInLobby(uid uint32, data []byte)
 
+	// CouldRequestStage 当有玩家请求切换房间阶段时调用，判断该玩家是否有权发起
+	// target 为请求进入的阶段：InLobby(返回大厅)、Preparing(进入准备)、PostGame(结束游戏)
+	// 返回 false 时请求被忽略；默认实现为仅房主可以发起
+	CouldRequestStage(uid uint32, isOwner bool, target constants.Stage) bool
+
+	// OnStageChanged 房间阶段切换后调用，此时 ResponseStageChange 已广播
+	// 进入 InGame 时在 OnGameStart 之后调用
+	OnStageChanged(oldStage, newStage constants.Stage)
+
 	// OnHandleToPreparingStage 当有玩家请求进入准备阶段时调用
 	OnHandleToPreparingStage(uid uint32, data []byte) (canEnter bool)
 
 	// OnHandleReady 当有玩家在准备阶段切换准备状态时调用
 	OnHandleReady(uid uint32, isReady bool, extraData []byte)
 
+	// OnReadyTimeout 准备倒计时结束时仍有玩家未准备时调用，返回对这些玩家的处理方式
+	// 倒计时在足够多的玩家准备后开始，见 LockstepConfig.ReadyCountdown
+	OnReadyTimeout(unreadyUIDs []uint32) ReadyTimeoutPolicy
+
 	// OnHandleAllReady 当所有玩家准备好时调用
 	OnHandleAllReady() (extraData []byte)
 
@@ -35,13 +60,25 @@ type IGameWorld interface {
 	// OnHandleLoaded 当有玩家在加载阶段时调用
 	OnHandleLoaded(uid uint32)
 
+	// OnLoadingTimeout 加载超时时仍有玩家未加载完毕时调用，返回对这些玩家的处理方式
+	// 超时时长见 LockstepConfig.LoadingTimeout
+	OnLoadingTimeout(unloadedUIDs []uint32) LoadingTimeoutPolicy
+
 	// InGame
 
+	// OnGameStart 当所有玩家加载完毕、房间进入 InGame 阶段时调用
+	// 此后核心框架的帧时钟每隔 frameInterval 步进一帧，第 1 帧在一个帧间隔后开始
+	OnGameStart(frameInterval time.Duration)
+
 	// OnReceiveData 核心框架收到客户端的原始数据包后，直接透传给此方法
 	// 这是外部游戏世界处理用户输入的核心入口
 	//
 	// 游戏世界需要自行处理例如延迟补偿等机制
-	OnReceiveClientInput(uid uint32, data ClientInputData)
+	OnReceiveClientInput(uid uint32, data *ClientInputData)
+	// OnInputTimeout 悲观锁步下，等待输入超时后调用
+	// 核心框架已通过 OnReceiveClientInput 为 uids 中的迟到玩家补上 frameId 帧的空输入
+	OnInputTimeout(frameId uint32, uids []uint32)
+
 	// OnReceiveOtherData 当有玩家发送其他自定义数据时调用
 	OnReceiveOtherData(uid uint32, data []byte)
 
@@ -51,11 +88,13 @@ type IGameWorld interface {
 	// OnHandlePostGameData 当有玩家在游戏结束后发送数据时调用
 	OnHandlePostGameData(uid uint32, data []byte) (backToLobby bool)
 
-	// OnTick 核心框架的 lockstep 时钟每帧会调用此方法
+	// Tick 核心框架的 lockstep 帧时钟每帧会调用此方法
 	// 游戏世界需要在此方法内处理本帧所有输入，并推进游戏状态
+	// 每帧固定先调用 Tick()，再调用 GetFrameData()
 	Tick()
 
 	// GetFrameData 核心框架在 Tick() 之后调用多次此方法做到自适应ack冗余发送
+	// 每帧先获取默认 chunk 0 的帧数据，再依次获取其他被订阅的 chunk 的帧数据
 	// 外部游戏世界在接受了“必定来自过去”的帧操作后，需要转发用户操作和游戏事件
 	// 因此本方法用于从外部游戏世界获取需要 “广播给所有客户端” 的同步数据
 	// 即操作序列和权威事件列表(不能仅在客户端预测的事件，如技能释放，伤害计算等)
@@ -63,15 +102,43 @@ type IGameWorld interface {
 	// frameId : 为了从(frameId-1)跳到frameId所需的帧数据
 	GetFrameData(frameId uint32, o WorldOptions) FrameData
 
+	// OnChunkSubscribe 当有玩家或观战者请求订阅 chunk 时调用，返回 false 时拒绝订阅
+	// 订阅成功后，核心框架从下一帧开始每帧以 WorldOptions.ChunkID 调用 GetFrameData 获取该 chunk 的帧数据
+	// 游戏世界可在该 chunk 随后的帧事件中携带其状态，供刚订阅的客户端初始化
+	// 订阅在对局之间保留，回到大厅时不会调用 OnChunkUnsubscribe
+	OnChunkSubscribe(uid uint32, chunkID int) (allowed bool)
+
+	// OnChunkUnsubscribe 当有玩家或观战者取消订阅 chunk 时调用
+	// 客户端离开房间时不会逐个调用，请在 OnPlayerLeave / OnSpectatorLeave 中处理
+	OnChunkUnsubscribe(uid uint32, chunkID int)
+
 	// GetSnapshot 获取状态快照，以方便拉帧快进
 	// frameId : 操作处理完后的已经步进到达的帧号
 	GetSnapshot(frameId uint32, o WorldOptions) Snapshot
 
+	// GetRoomMetadata 获取展示在房间列表与房间详情中的附加信息，例如地图、模式
+	// 核心框架在房间主循环中周期性调用并缓存结果，返回 nil 表示没有附加信息
+	GetRoomMetadata() []byte
+
 	// OnDestroy 当房间销毁时调用，用于资源释放
 	OnDestroy()
 }
 
+// IChecksumWorld 是游戏世界可选实现的接口，用于检测客户端不同步
+// 实现后，核心框架会校验客户端在 RequestInGameFrames 中上报的状态校验和
+type IChecksumWorld interface {
+	// GetChecksum 核心框架每帧在 Tick() 与 GetFrameData() 之后调用
+	// 返回步进到达 frameId 帧后的权威状态校验和，算法需与客户端一致
+	GetChecksum(frameId uint32) uint64
+
+	// OnDesync 当有玩家上报的校验和与 frameId 帧的权威校验和不一致时调用
+	// 同一帧同时不一致的玩家会合并为一次调用，同一玩家在重新同步前只会报告一次
+	// 返回 true 时核心框架向这些玩家发送最新的权威快照以重新同步
+	OnDesync(frameId uint32, uids []uint32) (resync bool)
+}
+
 type WorldOptions struct {
-	// FUTURE: 未来将拓展为多chunk以方便lockstep场景下的大世界
+	// 分 chunk 的大世界中本次获取的 chunk，默认 chunk 为 0
+	// 客户端通过 RequestSubscribeChunks 订阅 chunk，只接收所订阅 chunk 的帧数据
 	ChunkID int
 }
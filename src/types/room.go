@@ -4,8 +4,20 @@ import "lockstep-core/src/constants"
 
 // RoomInfo 房间信息
 type RoomInfo struct {
-	RoomID      string          `json:"room_id"`
-	NeedKey     bool            `json:"need_key"`
-	PlayerCount int             `json:"player_count"`
-	GameState   constants.Stage `json:"game_state"` // 游戏状态
+	RoomID         uint32          `json:"room_id"`
+	Name           string          `json:"name"`
+	NeedKey        bool            `json:"need_key"`
+	PlayerCount    int             `json:"player_count"`
+	MaxPlayers     int             `json:"max_players"`
+	SpectatorCount int             `json:"spectator_count"` // 当前观战者数量
+	MaxSpectators  int             `json:"max_spectators"`  // 最大观战者数量，0 为不允许观战
+	GameState      constants.Stage `json:"game_state"`      // 游戏状态
+	OwnerID        *uint32         `json:"owner_id"`        // 房主的玩家ID，房间内没有玩家时为 nil
+	Metadata       []byte          `json:"metadata"`        // 游戏世界提供的附加信息
+	Replay         bool            `json:"replay"`          // 是否为播放回放的只读房间
+}
+
+// HasFreeSlots 房间是否还有空余席位
+func (info *RoomInfo) HasFreeSlots() bool {
+	return info.PlayerCount < info.MaxPlayers
 }